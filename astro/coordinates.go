@@ -0,0 +1,35 @@
+package astro
+
+import "math"
+
+// Obliquity é a obliquidade média da eclíptica em J2000 (radianos).
+const Obliquity = 23.4392911 * math.Pi / 180
+
+// SphericalToCartesian converte longitude/latitude (radianos) em um vetor unitário.
+// Serve tanto para (AR, Dec) equatoriais quanto para (λ, β) eclípticas.
+func SphericalToCartesian(lon, lat float64) Vec3 {
+	sl, cl := math.Sincos(lon)
+	sb, cb := math.Sincos(lat)
+	return Vec3{cb * cl, cb * sl, sb}
+}
+
+// CartesianToSpherical é a inversa de SphericalToCartesian; a longitude
+// retornada fica em [0, 2π).
+func CartesianToSpherical(v Vec3) (lon, lat float64) {
+	lon = math.Atan2(v.Y, v.X)
+	if lon < 0 {
+		lon += 2 * math.Pi
+	}
+	lat = math.Atan2(v.Z, math.Hypot(v.X, v.Y))
+	return lon, lat
+}
+
+// EquatorialToEcliptic converte um vetor equatorial J2000 para a eclíptica J2000.
+func EquatorialToEcliptic(v Vec3) Vec3 {
+	return v.RotateX(-Obliquity)
+}
+
+// EclipticToEquatorial converte um vetor da eclíptica J2000 para o equador J2000.
+func EclipticToEquatorial(v Vec3) Vec3 {
+	return v.RotateX(Obliquity)
+}
@@ -0,0 +1,290 @@
+package astro
+
+import (
+	"math"
+	"sort"
+)
+
+// Raios médios usados na geometria das sombras, em UA.
+const (
+	SunRadius  = 696000.0 / AU
+	MoonRadius = 1737.4 / AU
+)
+
+// planetRadii são os raios equatoriais (km) dos planetas que transitam o Sol.
+var planetRadii = map[Body]float64{
+	Mercury: 2439.7,
+	Venus:   6051.8,
+}
+
+// umbraEnlargement aumenta a sombra da Terra em 2% para imitar a atmosfera,
+// como fazem as efemérides de eclipses lunares.
+const umbraEnlargement = 1.02
+
+// EclipseKind distingue eclipses solares, lunares e trânsitos planetários.
+type EclipseKind int
+
+const (
+	SolarEclipse EclipseKind = iota
+	LunarEclipse
+	MercuryTransit
+	VenusTransit
+)
+
+var eclipseKindNames = [...]string{"Eclipse solar", "Eclipse lunar", "Trânsito de Mercúrio", "Trânsito de Vênus"}
+
+func (k EclipseKind) String() string {
+	return eclipseKindNames[k]
+}
+
+// EclipseType é a classificação do evento no máximo.
+type EclipseType int
+
+const (
+	Partial EclipseType = iota
+	Total
+	Annular
+	Penumbral
+	Transit
+)
+
+var eclipseTypeNames = [...]string{"parcial", "total", "anular", "penumbral", "trânsito"}
+
+func (t EclipseType) String() string {
+	return eclipseTypeNames[t]
+}
+
+// Contact é um instante de contato: P1/P4 marcam a penumbra, U1 a U4 a umbra
+// e I a IV os contatos externos e internos de um trânsito.
+type Contact struct {
+	Name string
+	JD   float64
+}
+
+// Eclipse é uma ocultação encontrada por FindEclipses ou FindTransits. Os
+// contatos vêm em ordem cronológica.
+type Eclipse struct {
+	Kind     EclipseKind
+	Type     EclipseType
+	Maximum  float64 // data juliana do máximo
+	Distance float64 // distância mínima ao eixo da sombra (UA) ou separação (rad)
+	Contacts []Contact
+}
+
+// occultation descreve a geometria de um tipo de evento no instante jd: a
+// distância do corpo ao eixo da sombra (ou a separação angular) e os limiares
+// de contato, do mais externo para o mais interno. Um limiar negativo indica
+// que o contato não existe (por exemplo, a umbra da Lua não alcança a Terra).
+type occultation func(jd float64) (dist float64, levels []float64)
+
+// solarGeometry mede o eixo da sombra da Lua em relação ao centro da Terra.
+// Os limiares são o toque da penumbra e o da umbra (ou antumbra) na Terra.
+func solarGeometry(jd float64) (float64, []float64) {
+	sun := HeliocentricPosition(Earth, jd).Scale(-1)
+	moon := GeocentricMoon(jd)
+	axis := moon.Sub(sun)
+	length := axis.Norm()
+	u := axis.Scale(1 / length)
+	s := moon.Scale(-1).Dot(u) // distância da Lua à Terra ao longo do eixo
+	if s <= 0 {
+		return math.Inf(1), nil
+	}
+	d := moon.Scale(-1).Sub(u.Scale(s)).Norm()
+	penumbra := MoonRadius + s*(SunRadius+MoonRadius)/length
+	umbra := MoonRadius - s*(SunRadius-MoonRadius)/length
+	return d, []float64{EarthRadius + penumbra, EarthRadius + math.Abs(umbra)}
+}
+
+// solarUmbra devolve o raio da umbra da Lua na Terra; negativo quando só a
+// antumbra chega, o que torna o eclipse anular.
+func solarUmbra(jd float64) float64 {
+	sun := HeliocentricPosition(Earth, jd).Scale(-1)
+	moon := GeocentricMoon(jd)
+	length := moon.Sub(sun).Norm()
+	return MoonRadius - moon.Norm()*(SunRadius-MoonRadius)/length
+}
+
+// lunarGeometry mede a Lua em relação ao eixo da sombra da Terra. Os limiares
+// são os contatos com a penumbra, a umbra e o interior da umbra.
+func lunarGeometry(jd float64) (float64, []float64) {
+	sun := HeliocentricPosition(Earth, jd).Scale(-1)
+	moon := GeocentricMoon(jd)
+	dist := sun.Norm()
+	u := sun.Scale(-1 / dist)
+	s := moon.Dot(u)
+	if s <= 0 {
+		return math.Inf(1), nil
+	}
+	d := moon.Sub(u.Scale(s)).Norm()
+	penumbra := umbraEnlargement * (EarthRadius + s*(SunRadius+EarthRadius)/dist)
+	umbra := umbraEnlargement * (EarthRadius - s*(SunRadius-EarthRadius)/dist)
+	return d, []float64{penumbra + MoonRadius, umbra + MoonRadius, umbra - MoonRadius}
+}
+
+// transitGeometry mede a separação angular entre o planeta e o centro do Sol
+// vista do centro da Terra, com os contatos externos e internos.
+func transitGeometry(b Body) occultation {
+	radius := planetRadii[b] / AU
+	return func(jd float64) (float64, []float64) {
+		earth := HeliocentricPosition(Earth, jd)
+		sun := earth.Scale(-1)
+		planet := HeliocentricPosition(b, jd).Sub(earth)
+		if planet.Norm() >= sun.Norm() {
+			return math.Inf(1), nil
+		}
+		sep := math.Acos(math.Max(-1, math.Min(1, planet.Unit().Dot(sun.Unit()))))
+		sunDisk := math.Asin(SunRadius / sun.Norm())
+		disk := math.Asin(radius / planet.Norm())
+		return sep, []float64{sunDisk + disk, sunDisk - disk}
+	}
+}
+
+// localMinima percorre [start, end] com o passo step e refina por seção
+// áurea cada mínimo local da distância de f.
+func localMinima(f occultation, start, end, step float64) []float64 {
+	dist := func(jd float64) float64 { d, _ := f(jd); return d }
+	var minima []float64
+	prev, cur := dist(start), dist(start+step)
+	for t := start + step; t+step <= end; t += step {
+		next := dist(t + step)
+		if cur < prev && cur <= next && !math.IsInf(cur, 1) {
+			minima = append(minima, goldenMinimum(dist, t-step, t+step))
+		}
+		prev, cur = cur, next
+	}
+	return minima
+}
+
+// goldenMinimum localiza o mínimo de f em [a, b] por seção áurea.
+func goldenMinimum(f func(float64) float64, a, b float64) float64 {
+	ratio := (math.Sqrt(5) - 1) / 2
+	c, d := b-ratio*(b-a), a+ratio*(b-a)
+	fc, fd := f(c), f(d)
+	for b-a > 1e-6 {
+		if fc < fd {
+			b, d, fd = d, c, fc
+			c = b - ratio*(b-a)
+			fc = f(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + ratio*(b-a)
+			fd = f(d)
+		}
+	}
+	return (a + b) / 2
+}
+
+// crossing encontra por bissecção o instante entre inside e outside em que a
+// distância cruza o limiar level do contato k.
+func crossing(f occultation, k int, inside, outside float64) float64 {
+	excess := func(jd float64) float64 {
+		d, levels := f(jd)
+		if k >= len(levels) {
+			return math.Inf(1)
+		}
+		return d - levels[k]
+	}
+	for math.Abs(outside-inside) > 1e-6 {
+		mid := (inside + outside) / 2
+		if excess(mid) < 0 {
+			inside = mid
+		} else {
+			outside = mid
+		}
+	}
+	return (inside + outside) / 2
+}
+
+// findOccultations busca os eventos de f entre start e end. Cada mínimo
+// abaixo do limiar externo vira um evento, com os contatos de cada limiar
+// atravessado buscados até window dias antes e depois do máximo.
+func findOccultations(f occultation, start, end, step, window float64, names [][2]string) []Eclipse {
+	var events []Eclipse
+	for _, tmax := range localMinima(f, start, end, step) {
+		d, levels := f(tmax)
+		if len(levels) == 0 || d >= levels[0] {
+			continue
+		}
+		e := Eclipse{Maximum: tmax, Distance: d}
+		var before, after []Contact
+		for k, level := range levels {
+			if level <= 0 || d >= level {
+				break
+			}
+			before = append(before, Contact{names[k][0], crossing(f, k, tmax, tmax-window)})
+			after = append([]Contact{{names[k][1], crossing(f, k, tmax, tmax+window)}}, after...)
+		}
+		e.Contacts = append(append(before, Contact{"Máximo", tmax}), after...)
+		events = append(events, e)
+	}
+	return events
+}
+
+// FindEclipses procura os eclipses solares e lunares entre as datas julianas
+// start e end, vistos do centro da Terra. Eclipses solares são globais:
+// partial quando só a penumbra da Lua toca a Terra, total ou anular quando
+// a umbra (ou antumbra) alcança a superfície.
+func FindEclipses(start, end float64) []Eclipse {
+	const step, window = 1.0 / 24, 0.5
+	var events []Eclipse
+
+	solar := findOccultations(solarGeometry, start, end, step, window,
+		[][2]string{{"P1", "P4"}, {"U1", "U4"}})
+	for _, e := range solar {
+		e.Kind, e.Type = SolarEclipse, Partial
+		if len(e.Contacts) > 3 {
+			e.Type = Total
+			if solarUmbra(e.Maximum) < 0 {
+				e.Type = Annular
+			}
+		}
+		events = append(events, e)
+	}
+
+	lunar := findOccultations(lunarGeometry, start, end, step, window,
+		[][2]string{{"P1", "P4"}, {"U1", "U4"}, {"U2", "U3"}})
+	for _, e := range lunar {
+		e.Kind = LunarEclipse
+		switch len(e.Contacts) {
+		case 3:
+			e.Type = Penumbral
+		case 5:
+			e.Type = Partial
+		default:
+			e.Type = Total
+		}
+		events = append(events, e)
+	}
+
+	sortEclipses(events)
+	return events
+}
+
+// FindTransits procura os trânsitos de Mercúrio e Vênus pelo disco solar
+// entre start e end, vistos do centro da Terra.
+func FindTransits(start, end float64) []Eclipse {
+	const step, window = 0.1, 1.0
+	var events []Eclipse
+	for _, b := range []Body{Mercury, Venus} {
+		kind := MercuryTransit
+		if b == Venus {
+			kind = VenusTransit
+		}
+		found := findOccultations(transitGeometry(b), start, end, step, window,
+			[][2]string{{"I", "IV"}, {"II", "III"}})
+		for _, e := range found {
+			e.Kind, e.Type = kind, Transit
+			events = append(events, e)
+		}
+	}
+	sortEclipses(events)
+	return events
+}
+
+func sortEclipses(events []Eclipse) {
+	sort.Slice(events, func(i, j int) bool { return events[i].Maximum < events[j].Maximum })
+}
+
+// Start e End são o primeiro e o último contato do evento.
+func (e Eclipse) Start() float64 { return e.Contacts[0].JD }
+func (e Eclipse) End() float64   { return e.Contacts[len(e.Contacts)-1].JD }
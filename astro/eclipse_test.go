@@ -0,0 +1,64 @@
+package astro
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// eclipseTolerance é o erro aceito no instante do máximo.
+const eclipseTolerance = 15 * time.Minute
+
+func TestFindEclipses(t *testing.T) {
+	// Máximos publicados pela NASA (eclipse.gsfc.nasa.gov), em UTC
+	tests := []struct {
+		kind    EclipseKind
+		typ     EclipseType
+		maximum time.Time
+	}{
+		{SolarEclipse, Annular, time.Date(2023, 10, 14, 17, 59, 0, 0, time.UTC)},
+		{LunarEclipse, Partial, time.Date(2023, 10, 28, 20, 14, 0, 0, time.UTC)},
+		{LunarEclipse, Penumbral, time.Date(2024, 3, 25, 7, 12, 0, 0, time.UTC)},
+		{SolarEclipse, Total, time.Date(2024, 4, 8, 18, 17, 0, 0, time.UTC)},
+		{LunarEclipse, Partial, time.Date(2024, 9, 18, 2, 44, 0, 0, time.UTC)},
+		{SolarEclipse, Annular, time.Date(2024, 10, 2, 18, 45, 0, 0, time.UTC)},
+		{LunarEclipse, Total, time.Date(2025, 3, 14, 6, 58, 0, 0, time.UTC)},
+		{SolarEclipse, Partial, time.Date(2025, 3, 29, 10, 47, 0, 0, time.UTC)},
+	}
+	start := JulianDate(time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC))
+	end := JulianDate(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC))
+	got := FindEclipses(start, end)
+	if len(got) != len(tests) {
+		t.Fatalf("%d eclipses encontrados, esperados %d", len(got), len(tests))
+	}
+	for i, tt := range tests {
+		e := got[i]
+		if e.Kind != tt.kind || e.Type != tt.typ {
+			t.Errorf("%s: encontrado %s %s, esperado %s %s", tt.maximum.Format(time.DateOnly), e.Kind, e.Type, tt.kind, tt.typ)
+		}
+		checkMaximum(t, e, tt.maximum)
+	}
+}
+
+func TestFindTransits(t *testing.T) {
+	start := JulianDate(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	end := JulianDate(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	got := FindTransits(start, end)
+	if len(got) != 1 || got[0].Kind != MercuryTransit {
+		t.Fatalf("encontrado %v, esperado o trânsito de Mercúrio de 2019", got)
+	}
+	checkMaximum(t, got[0], time.Date(2019, 11, 11, 15, 20, 0, 0, time.UTC))
+}
+
+// checkMaximum confere o instante do máximo e a ordem dos contatos.
+func checkMaximum(t *testing.T, e Eclipse, want time.Time) {
+	t.Helper()
+	if d := TimeFromJulian(e.Maximum).Sub(want); math.Abs(float64(d)) > float64(eclipseTolerance) {
+		t.Errorf("%s de %s: máximo às %s", e.Kind, want.Format(time.DateOnly), TimeFromJulian(e.Maximum).Format(time.TimeOnly))
+	}
+	for k := 1; k < len(e.Contacts); k++ {
+		if e.Contacts[k].JD < e.Contacts[k-1].JD {
+			t.Errorf("%s de %s: contatos fora de ordem: %v", e.Kind, want.Format(time.DateOnly), e.Contacts)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package astro
+
+import "math"
+
+// OrbitalElements são os elementos osculantes de uma órbita kepleriana. Os
+// ângulos estão em radianos; distâncias e tempo seguem as unidades de mu.
+type OrbitalElements struct {
+	A           float64 // semieixo maior (negativo para hipérboles)
+	E           float64 // excentricidade
+	I           float64 // inclinação
+	Node        float64 // longitude do nodo ascendente (Ω)
+	ArgPeri     float64 // argumento do periastro (ω)
+	TrueAnomaly float64 // anomalia verdadeira (ν)
+	Period      float64 // +Inf para órbitas abertas
+	Periapsis   float64
+	Apoapsis    float64 // +Inf para órbitas abertas
+}
+
+// elementsEpsilon é o limite abaixo do qual a órbita é tratada como circular
+// ou equatorial, quando Ω e ω deixam de ser definidos.
+const elementsEpsilon = 1e-9
+
+// ElementsFromState calcula os elementos osculantes a partir da posição e da
+// velocidade relativas ao corpo central de parâmetro mu. Em órbitas
+// equatoriais Ω vale zero e ω é medido a partir do eixo x; em órbitas
+// circulares ω vale zero e ν é medido a partir do nodo (ou do eixo x).
+func ElementsFromState(r, v Vec3, mu float64) OrbitalElements {
+	rn := r.Norm()
+	h := r.Cross(v)
+	hn := h.Norm()
+	n := Vec3{Z: 1}.Cross(h)
+	ev := r.Scale(v.Dot(v) - mu/rn).Sub(v.Scale(r.Dot(v))).Scale(1 / mu)
+
+	var el OrbitalElements
+	el.E = ev.Norm()
+	energy := v.Dot(v)/2 - mu/rn
+	el.A = -mu / (2 * energy)
+	el.I = math.Acos(math.Max(-1, math.Min(1, h.Z/hn)))
+
+	equatorial := n.Norm() < elementsEpsilon*hn
+	circular := el.E < elementsEpsilon
+	if !equatorial {
+		el.Node = normalizeAngle(math.Atan2(n.Y, n.X))
+	}
+
+	// Direção de referência para ω: o nodo ou, sem nodo, o eixo x
+	ref := Vec3{X: 1}
+	if !equatorial {
+		ref = n.Unit()
+	}
+	angleFrom := func(from, to Vec3) float64 {
+		a := math.Atan2(from.Cross(to).Dot(h)/hn, from.Dot(to))
+		return normalizeAngle(a)
+	}
+	if circular {
+		el.TrueAnomaly = angleFrom(ref, r)
+	} else {
+		el.ArgPeri = angleFrom(ref, ev)
+		el.TrueAnomaly = angleFrom(ev, r)
+	}
+
+	el.Periapsis = hn * hn / mu / (1 + el.E)
+	el.Period, el.Apoapsis = math.Inf(1), math.Inf(1)
+	if el.E < 1 {
+		el.Period = 2 * math.Pi * math.Sqrt(el.A*el.A*el.A/mu)
+		el.Apoapsis = el.A * (1 + el.E)
+	}
+	return el
+}
+
+// PositionAt devolve a posição relativa ao corpo central na anomalia
+// verdadeira nu, usada para desenhar a órbita osculante.
+func (el OrbitalElements) PositionAt(nu float64) Vec3 {
+	p := el.Periapsis * (1 + el.E)
+	r := p / (1 + el.E*math.Cos(nu))
+	s, c := math.Sincos(nu)
+	return Vec3{X: r * c, Y: r * s}.RotateZ(el.ArgPeri).RotateX(el.I).RotateZ(el.Node)
+}
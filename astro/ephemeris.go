@@ -0,0 +1,283 @@
+package astro
+
+import (
+	"math"
+	"strings"
+)
+
+// AU é a unidade astronômica em quilômetros.
+const AU = 149597870.7
+
+// Body identifica um corpo do Sistema Solar com efemérides.
+type Body int
+
+const (
+	Sun Body = iota
+	Mercury
+	Venus
+	Earth
+	Moon
+	Mars
+	Jupiter
+	Saturn
+	Uranus
+	Neptune
+	Pluto
+)
+
+// Bodies lista todos os corpos conhecidos, na ordem de declaração.
+var Bodies = []Body{Sun, Mercury, Venus, Earth, Moon, Mars, Jupiter, Saturn, Uranus, Neptune, Pluto}
+
+var bodyNames = [...]string{"Sol", "Mercúrio", "Vênus", "Terra", "Lua", "Marte", "Júpiter", "Saturno", "Urano", "Netuno", "Plutão"}
+
+// String retorna o nome do corpo em português.
+func (b Body) String() string {
+	if b < 0 || int(b) >= len(bodyNames) {
+		return "desconhecido"
+	}
+	return bodyNames[b]
+}
+
+// bodyAliases aceita os nomes usados pelos visualizadores (com e sem acento) e em inglês.
+var bodyAliases = map[string]Body{
+	"sol": Sun, "sun": Sun,
+	"mercurio": Mercury, "mercury": Mercury,
+	"venus": Venus,
+	"terra": Earth, "earth": Earth,
+	"lua": Moon, "moon": Moon,
+	"marte": Mars, "mars": Mars,
+	"jupiter": Jupiter,
+	"saturno": Saturn, "saturn": Saturn,
+	"urano": Uranus, "uranus": Uranus,
+	"netuno": Neptune, "neptune": Neptune,
+	"plutao": Pluto, "pluto": Pluto,
+}
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e", "í", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ú", "u", "ç", "c",
+)
+
+// BodyByName procura um corpo pelo nome, ignorando acentos e maiúsculas.
+func BodyByName(name string) (Body, bool) {
+	b, ok := bodyAliases[accentReplacer.Replace(strings.ToLower(strings.TrimSpace(name)))]
+	return b, ok
+}
+
+// keplerElements são os elementos orbitais médios de Standish (JPL, válidos de
+// 1800 a 2050) na época J2000 e suas taxas por século. Ângulos em graus.
+type keplerElements struct {
+	a, e, i, l, peri, node       float64
+	da, de, di, dl, dperi, dnode float64
+}
+
+// Para a Terra os elementos são os do baricentro Terra–Lua.
+var planetElements = map[Body]keplerElements{
+	Mercury: {0.38709927, 0.20563593, 7.00497902, 252.25032350, 77.45779628, 48.33076593,
+		0.00000037, 0.00001906, -0.00594749, 149472.67411175, 0.16047689, -0.12534081},
+	Venus: {0.72333566, 0.00677672, 3.39467605, 181.97909950, 131.60246718, 76.67984255,
+		0.00000390, -0.00004107, -0.00078890, 58517.81538729, 0.00268329, -0.27769418},
+	Earth: {1.00000261, 0.01671123, -0.00001531, 100.46457166, 102.93768193, 0.0,
+		0.00000562, -0.00004392, -0.01294668, 35999.37244981, 0.32327364, 0.0},
+	Mars: {1.52371034, 0.09339410, 1.84969142, -4.55343205, -23.94362959, 49.55953891,
+		0.00001847, 0.00007882, -0.00813131, 19140.30268499, 0.44441088, -0.29257343},
+	Jupiter: {5.20288700, 0.04838624, 1.30439695, 34.39644051, 14.72847983, 100.47390909,
+		-0.00011607, -0.00013253, -0.00183714, 3034.74612775, 0.21252668, 0.20469106},
+	Saturn: {9.53667594, 0.05386179, 2.48599187, 49.95424423, 92.59887831, 113.66242448,
+		-0.00125060, -0.00050991, 0.00193609, 1222.49362201, -0.41897216, -0.28867794},
+	Uranus: {19.18916464, 0.04725744, 0.77263783, 313.23810451, 170.95427630, 74.01692503,
+		-0.00196176, -0.00004397, -0.00242939, 428.48202785, 0.40805281, 0.04240589},
+	Neptune: {30.06992276, 0.00859048, 1.77004347, -55.12002969, 44.96476227, 131.78422574,
+		0.00026291, 0.00005105, 0.00035372, 218.45945325, -0.32241464, -0.00508664},
+	Pluto: {39.48211675, 0.24882730, 17.14001206, 238.92903833, 224.06891629, 110.30393684,
+		-0.00031596, 0.00005170, 0.00004818, 145.20780515, -0.04062942, -0.01183482},
+}
+
+// earthMoonMassRatio é a razão entre as massas da Terra e da Lua.
+const earthMoonMassRatio = 81.30056
+
+// HeliocentricPosition retorna a posição do corpo relativa ao Sol, em UA, na
+// eclíptica J2000.
+func HeliocentricPosition(b Body, jd float64) Vec3 {
+	switch b {
+	case Sun:
+		return Vec3{}
+	case Earth:
+		emb := keplerPosition(planetElements[Earth], jd)
+		return emb.Sub(GeocentricMoon(jd).Scale(1 / (1 + earthMoonMassRatio)))
+	case Moon:
+		return HeliocentricPosition(Earth, jd).Add(GeocentricMoon(jd))
+	}
+	return keplerPosition(planetElements[b], jd)
+}
+
+// HeliocentricVelocity retorna a velocidade heliocêntrica do corpo (UA/dia),
+// por diferença central das posições.
+func HeliocentricVelocity(b Body, jd float64) Vec3 {
+	const h = 0.01
+	return HeliocentricPosition(b, jd+h).Sub(HeliocentricPosition(b, jd-h)).Scale(1 / (2 * h))
+}
+
+// keplerPosition resolve a órbita kepleriana dos elementos na data juliana.
+func keplerPosition(el keplerElements, jd float64) Vec3 {
+	t := centuries(jd)
+	a := el.a + el.da*t
+	e := el.e + el.de*t
+	i := rad(el.i + el.di*t)
+	l := el.l + el.dl*t
+	peri := el.peri + el.dperi*t
+	node := rad(el.node + el.dnode*t)
+	w := rad(peri) - node
+	m := normalizeAngle(rad(l - peri))
+
+	ea := SolveKepler(m, e)
+	xp := a * (math.Cos(ea) - e)
+	yp := a * math.Sqrt(1-e*e) * math.Sin(ea)
+
+	// Plano orbital → eclíptica: rotações por ω, i e Ω
+	return Vec3{xp, yp, 0}.RotateZ(w).RotateX(i).RotateZ(node)
+}
+
+// SolveKepler resolve a equação de Kepler M = E − e·sen E pelo método de Newton.
+func SolveKepler(m, e float64) float64 {
+	ea := m
+	if e > 0.8 {
+		ea = math.Pi
+	}
+	for k := 0; k < 30; k++ {
+		d := (ea - e*math.Sin(ea) - m) / (1 - e*math.Cos(ea))
+		ea -= d
+		if math.Abs(d) < 1e-12 {
+			break
+		}
+	}
+	return ea
+}
+
+// moonTerm é um termo periódico da teoria lunar: múltiplos de D, M, M' e F e
+// os coeficientes de longitude (1e-6 grau) e distância (metros).
+type moonTerm struct {
+	d, m, mp, f float64
+	sl, sr      float64
+}
+
+// Principais termos das tabelas 47.A e 47.B de Meeus (Astronomical Algorithms).
+var moonLonDist = []moonTerm{
+	{0, 0, 1, 0, 6288774, -20905355},
+	{2, 0, -1, 0, 1274027, -3699111},
+	{2, 0, 0, 0, 658314, -2955968},
+	{0, 0, 2, 0, 213618, -569925},
+	{0, 1, 0, 0, -185116, 48888},
+	{0, 0, 0, 2, -114332, -3149},
+	{2, 0, -2, 0, 58793, 246158},
+	{2, -1, -1, 0, 57066, -152138},
+	{2, 0, 1, 0, 53322, -170733},
+	{2, -1, 0, 0, 45758, -204586},
+	{0, 1, -1, 0, -40923, -129620},
+	{1, 0, 0, 0, -34720, 108743},
+	{0, 1, 1, 0, -30383, 104755},
+	{2, 0, 0, -2, 15327, 10321},
+	{0, 0, 1, 2, -12528, 0},
+	{0, 0, 1, -2, 10980, 79661},
+	{4, 0, -1, 0, 10675, -34782},
+	{0, 0, 3, 0, 10034, -23210},
+	{4, 0, -2, 0, 8548, -21636},
+	{2, 1, -1, 0, -7888, 24208},
+	{2, 1, 0, 0, -6766, 30824},
+	{1, 0, -1, 0, -5163, -8379},
+	{1, 1, 0, 0, 4987, -16675},
+	{2, -1, 1, 0, 4036, -12831},
+	{2, 0, 2, 0, 3994, -10445},
+	{4, 0, 0, 0, 3861, -11650},
+	{2, 0, -3, 0, 3665, 14403},
+	{0, 1, -2, 0, -2689, -7003},
+	{2, 0, -1, 2, -2602, 0},
+	{2, -1, -2, 0, 2390, 10056},
+	{1, 0, 1, 0, -2348, 6322},
+	{2, -2, 0, 0, 2236, -9884},
+}
+
+// Termos de latitude (sl em 1e-6 grau; sr não é usado).
+var moonLat = []moonTerm{
+	{0, 0, 0, 1, 5128122, 0},
+	{0, 0, 1, 1, 280602, 0},
+	{0, 0, 1, -1, 277693, 0},
+	{2, 0, 0, -1, 173237, 0},
+	{2, 0, -1, 1, 55413, 0},
+	{2, 0, -1, -1, 46271, 0},
+	{2, 0, 0, 1, 32573, 0},
+	{0, 0, 2, 1, 17198, 0},
+	{2, 0, 1, -1, 9266, 0},
+	{0, 0, 2, -1, 8822, 0},
+	{2, -1, 0, -1, 8216, 0},
+	{2, 0, -2, -1, 4324, 0},
+	{2, 0, 1, 1, 4200, 0},
+	{2, 1, 0, -1, -3359, 0},
+	{2, -1, -1, 1, 2463, 0},
+	{2, -1, 0, 1, 2211, 0},
+	{2, -1, -1, -1, 2065, 0},
+	{0, 1, -1, -1, -1870, 0},
+	{4, 0, -1, -1, 1828, 0},
+	{0, 1, 0, 1, -1794, 0},
+}
+
+// GeocentricMoon retorna a posição da Lua relativa à Terra, em UA, na eclíptica
+// J2000. Usa os termos principais da teoria de Meeus (precisão de alguns
+// centésimos de grau), suficiente para eclipses e para o planetário.
+func GeocentricMoon(jd float64) Vec3 {
+	t := centuries(jd)
+	lp := rad(218.3164477 + 481267.88123421*t)
+	d := rad(297.8501921 + 445267.1114034*t)
+	m := rad(357.5291092 + 35999.0502909*t)
+	mp := rad(134.9633964 + 477198.8675055*t)
+	f := rad(93.2720950 + 483202.0175233*t)
+	ecc := 1 - 0.002516*t - 0.0000074*t*t
+
+	// Termos com M são atenuados pela excentricidade da órbita terrestre
+	factor := func(mult float64) float64 {
+		switch math.Abs(mult) {
+		case 1:
+			return ecc
+		case 2:
+			return ecc * ecc
+		}
+		return 1
+	}
+
+	var sl, sr, sb float64
+	for _, k := range moonLonDist {
+		arg := k.d*d + k.m*m + k.mp*mp + k.f*f
+		e := factor(k.m)
+		sl += k.sl * e * math.Sin(arg)
+		sr += k.sr * e * math.Cos(arg)
+	}
+	for _, k := range moonLat {
+		arg := k.d*d + k.m*m + k.mp*mp + k.f*f
+		sb += k.sl * factor(k.m) * math.Sin(arg)
+	}
+
+	a1 := rad(119.75 + 131.849*t)
+	a2 := rad(53.09 + 479264.290*t)
+	a3 := rad(313.45 + 481266.484*t)
+	sl += 3958*math.Sin(a1) + 1962*math.Sin(lp-f) + 318*math.Sin(a2)
+	sb += -2235*math.Sin(lp) + 382*math.Sin(a3) + 175*math.Sin(a1-f) +
+		175*math.Sin(a1+f) + 127*math.Sin(lp-mp) - 115*math.Sin(lp+mp)
+
+	// A teoria dá a longitude no equinócio da data; remove a precessão geral
+	// acumulada desde J2000 para voltar ao referencial J2000.
+	lon := lp + rad(sl/1e6) - rad(1.3969713*t)
+	lat := rad(sb / 1e6)
+	dist := (385000.56 + sr/1000) / AU
+	return SphericalToCartesian(lon, lat).Scale(dist)
+}
+
+// MoonInclination é a inclinação média da órbita da Lua sobre a eclíptica.
+const MoonInclination = 5.145 * math.Pi / 180
+
+// MoonNode retorna a longitude média do nodo ascendente da órbita lunar em
+// J2000 (rad). O nodo regride uma volta a cada ~18,6 anos.
+func MoonNode(jd float64) float64 {
+	t := centuries(jd)
+	return normalizeAngle(rad(125.0445479 - 1934.1362891*t - 1.3969713*t))
+}
@@ -0,0 +1,128 @@
+package astro
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FrameKind é o tipo de um referencial.
+type FrameKind int
+
+const (
+	// HeliocentricEcliptic tem origem no Sol e eixos na eclíptica J2000.
+	HeliocentricEcliptic FrameKind = iota
+	// Barycentric tem origem no baricentro do Sistema Solar e eixos na eclíptica J2000.
+	Barycentric
+	// GeocentricEquatorial tem origem na Terra e eixos no equador J2000.
+	GeocentricEquatorial
+	// BodyCentered tem origem em um corpo qualquer e eixos na eclíptica J2000.
+	BodyCentered
+)
+
+// Frame é um referencial; Center só é usado por BodyCentered.
+type Frame struct {
+	Kind   FrameKind
+	Center Body
+}
+
+// Referenciais mais usados.
+var (
+	HeliocentricFrame = Frame{Kind: HeliocentricEcliptic}
+	BarycentricFrame  = Frame{Kind: Barycentric}
+	GeocentricFrame   = Frame{Kind: GeocentricEquatorial}
+)
+
+// CenteredOn devolve o referencial eclíptico centrado no corpo.
+func CenteredOn(b Body) Frame {
+	return Frame{Kind: BodyCentered, Center: b}
+}
+
+// String descreve o referencial em português.
+func (f Frame) String() string {
+	switch f.Kind {
+	case HeliocentricEcliptic:
+		return "Heliocêntrico eclíptico (J2000)"
+	case Barycentric:
+		return "Baricêntrico eclíptico (J2000)"
+	case GeocentricEquatorial:
+		return "Geocêntrico equatorial (J2000)"
+	}
+	return "Centrado em " + f.Center.String()
+}
+
+// ParseFrame interpreta nomes como "helio", "bari", "geo" ou o nome de um corpo
+// (por exemplo "Marte"), usado pelos comandos de linha de comando.
+func ParseFrame(name string) (Frame, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "helio", "heliocentrico", "heliocêntrico":
+		return HeliocentricFrame, nil
+	case "bari", "baricentrico", "baricêntrico":
+		return BarycentricFrame, nil
+	case "geo", "geocentrico", "geocêntrico":
+		return GeocentricFrame, nil
+	}
+	if b, ok := BodyByName(name); ok {
+		return CenteredOn(b), nil
+	}
+	return Frame{}, fmt.Errorf("referencial desconhecido: %q", name)
+}
+
+// Origin retorna a posição heliocêntrica eclíptica (UA) da origem do referencial.
+func (f Frame) Origin(jd float64) Vec3 {
+	switch f.Kind {
+	case Barycentric:
+		return BarycenterOffset(jd)
+	case GeocentricEquatorial:
+		return HeliocentricPosition(Earth, jd)
+	case BodyCentered:
+		return HeliocentricPosition(f.Center, jd)
+	}
+	return Vec3{}
+}
+
+// Orient gira um vetor dos eixos eclípticos J2000 para os eixos do referencial,
+// sem transladar (serve também para direções e velocidades).
+func (f Frame) Orient(v Vec3) Vec3 {
+	if f.Kind == GeocentricEquatorial {
+		return EclipticToEquatorial(v)
+	}
+	return v
+}
+
+// Unorient é a inversa de Orient.
+func (f Frame) Unorient(v Vec3) Vec3 {
+	if f.Kind == GeocentricEquatorial {
+		return EquatorialToEcliptic(v)
+	}
+	return v
+}
+
+// FromHeliocentric converte uma posição heliocêntrica eclíptica para o referencial.
+func (f Frame) FromHeliocentric(r Vec3, jd float64) Vec3 {
+	return f.Orient(r.Sub(f.Origin(jd)))
+}
+
+// ToHeliocentric converte uma posição no referencial para heliocêntrica eclíptica.
+func (f Frame) ToHeliocentric(r Vec3, jd float64) Vec3 {
+	return f.Unorient(r).Add(f.Origin(jd))
+}
+
+// Convert leva uma posição de um referencial para outro na data jd.
+func Convert(r Vec3, from, to Frame, jd float64) Vec3 {
+	return to.FromHeliocentric(from.ToHeliocentric(r, jd), jd)
+}
+
+// BarycenterOffset retorna a posição heliocêntrica do baricentro do Sistema
+// Solar, ponderando as posições dos planetas (a Terra e a Lua separadas) por GM.
+func BarycenterOffset(jd float64) Vec3 {
+	var sum Vec3
+	total := GM(Sun)
+	for _, b := range Bodies {
+		if b == Sun {
+			continue
+		}
+		sum = sum.Add(HeliocentricPosition(b, jd).Scale(GM(b)))
+		total += GM(b)
+	}
+	return sum.Scale(1 / total)
+}
@@ -0,0 +1,53 @@
+package astro
+
+import "math"
+
+// gmKm são os parâmetros gravitacionais (GM) em km³/s², valores do JPL.
+var gmKm = map[Body]float64{
+	Sun:     1.32712440018e11,
+	Mercury: 2.2032e4,
+	Venus:   3.24859e5,
+	Earth:   3.986004418e5,
+	Moon:    4.9048695e3,
+	Mars:    4.282837e4,
+	Jupiter: 1.26686534e8,
+	Saturn:  3.7931187e7,
+	Uranus:  5.793939e6,
+	Neptune: 6.836529e6,
+	Pluto:   8.71e2,
+}
+
+// GM retorna o parâmetro gravitacional do corpo em UA³/dia².
+func GM(b Body) float64 {
+	return gmKm[b] * 86400 * 86400 / (AU * AU * AU)
+}
+
+// GMKm retorna o parâmetro gravitacional do corpo em km³/s².
+func GMKm(b Body) float64 {
+	return gmKm[b]
+}
+
+// MassRatio retorna m2/(m1+m2), o parâmetro de massa do problema restrito de três corpos.
+func MassRatio(primary, secondary Body) float64 {
+	return gmKm[secondary] / (gmKm[primary] + gmKm[secondary])
+}
+
+// SphereOfInfluence retorna o raio da esfera de influência de Laplace,
+// a·(gm/gmCentral)^(2/5), de um corpo de parâmetro gm orbitando a distância a
+// de um corpo central de parâmetro gmCentral (qualquer unidade de distância).
+func SphereOfInfluence(a, gm, gmCentral float64) float64 {
+	return a * math.Pow(gm/gmCentral, 0.4)
+}
+
+// HillRadius retorna o raio da esfera de Hill, a·(gm/(3·gmCentral))^(1/3), de
+// um corpo de parâmetro gm orbitando a distância a de um corpo central.
+func HillRadius(a, gm, gmCentral float64) float64 {
+	return a * math.Cbrt(gm/(3*gmCentral))
+}
+
+// CircularMu retorna o parâmetro gravitacional total, ω²·r³ (terceira lei de
+// Kepler), que torna circular uma órbita de raio r e velocidade angular ω, em
+// quaisquer unidades de distância e tempo.
+func CircularMu(omega, r float64) float64 {
+	return omega * omega * r * r * r
+}
@@ -0,0 +1,41 @@
+package astro
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCircularMu(t *testing.T) {
+	// Corpos da cena do visualizador: raio em pixels, ω em rad/frame e, para a
+	// Lua, a órbita inclinada em torno do nodo.
+	tests := []struct {
+		name       string
+		r, omega   float64
+		incl, node float64
+		angle      float64
+	}{
+		{name: "mercúrio", r: 80, omega: 0.04, angle: 0.3},
+		{name: "terra", r: 160, omega: 0.02, angle: 2},
+		{name: "netuno", r: 400, omega: 0.005, angle: -1.2},
+		{name: "lua inclinada", r: 20, omega: 0.05, incl: 5.145 * math.Pi / 180, node: 1.1, angle: 2.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, c := math.Sincos(tt.angle - tt.node)
+			r := Vec3{X: tt.r * c, Y: tt.r * s}.RotateX(tt.incl).RotateZ(tt.node)
+			normal := Vec3{Z: 1}.RotateX(tt.incl).RotateZ(tt.node)
+			v := normal.Cross(r).Scale(tt.omega)
+
+			el := ElementsFromState(r, v, CircularMu(tt.omega, tt.r))
+			if el.E > 1e-9 {
+				t.Errorf("e = %g, quer 0", el.E)
+			}
+			if math.Abs(el.A-tt.r) > 1e-9*tt.r {
+				t.Errorf("a = %g, quer %g", el.A, tt.r)
+			}
+			if want := 2 * math.Pi / tt.omega; math.Abs(el.Period-want) > 1e-9*want {
+				t.Errorf("período = %g, quer %g", el.Period, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package astro
+
+// AccelFunc devolve a aceleração de uma partícula na posição r no instante t.
+type AccelFunc func(t float64, r Vec3) Vec3
+
+// LeapfrogStep avança posição e velocidade por dt com o integrador leapfrog
+// (kick-drift-kick). É simplético, então conserva bem a energia em órbitas
+// longas, mesmo com passos relativamente grandes.
+func LeapfrogStep(t float64, r, v Vec3, dt float64, accel AccelFunc) (Vec3, Vec3) {
+	v = v.Add(accel(t, r).Scale(dt / 2))
+	r = r.Add(v.Scale(dt))
+	v = v.Add(accel(t+dt, r).Scale(dt / 2))
+	return r, v
+}
+
+// PointMassAccel devolve a aceleração, em r, causada por uma massa pontual de
+// parâmetro gravitacional gm situada em center.
+func PointMassAccel(r, center Vec3, gm float64) Vec3 {
+	d := center.Sub(r)
+	n := d.Norm()
+	if n == 0 {
+		return Vec3{}
+	}
+	return d.Scale(gm / (n * n * n))
+}
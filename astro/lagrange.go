@@ -0,0 +1,86 @@
+package astro
+
+import "math"
+
+// LagrangeNames são os rótulos dos cinco pontos, na ordem devolvida pelas funções.
+var LagrangeNames = [5]string{"L1", "L2", "L3", "L4", "L5"}
+
+// SynodicLagrangePoints devolve L1–L5 no referencial girante normalizado do
+// problema restrito de três corpos: baricentro na origem, primário em
+// (−mu, 0), secundário em (1−mu, 0), distância entre eles igual a 1.
+func SynodicLagrangePoints(mu float64) [5]Vec3 {
+	// Os pontos colineares são raízes de ∂Ω/∂x = 0 sobre o eixo x; o chute
+	// inicial vem da aproximação de Hill, r ≈ (mu/3)^(1/3).
+	h := math.Cbrt(mu / 3)
+	l1 := collinearRoot(mu, 1-mu-h)
+	l2 := collinearRoot(mu, 1-mu+h)
+	l3 := collinearRoot(mu, -1-5*mu/12)
+
+	s := math.Sqrt(3) / 2
+	return [5]Vec3{
+		{X: l1},
+		{X: l2},
+		{X: l3},
+		{X: 0.5 - mu, Y: s},
+		{X: 0.5 - mu, Y: -s},
+	}
+}
+
+// collinearRoot resolve ∂Ω/∂x = 0 no eixo x pelo método de Newton.
+func collinearRoot(mu, x float64) float64 {
+	for i := 0; i < 50; i++ {
+		d1, d2 := x+mu, x-1+mu
+		a1, a2 := math.Abs(d1), math.Abs(d2)
+		f := x - (1-mu)*d1/(a1*a1*a1) - mu*d2/(a2*a2*a2)
+		df := 1 + 2*(1-mu)/(a1*a1*a1) + 2*mu/(a2*a2*a2)
+		dx := f / df
+		x -= dx
+		if math.Abs(dx) < 1e-14 {
+			break
+		}
+	}
+	return x
+}
+
+// EffectivePotential é o potencial efetivo Ω(x, y) do referencial girante
+// normalizado (gravidade dos dois corpos mais o termo centrífugo).
+func EffectivePotential(mu, x, y float64) float64 {
+	r1 := math.Hypot(x+mu, y)
+	r2 := math.Hypot(x-1+mu, y)
+	return (x*x+y*y)/2 + (1-mu)/r1 + mu/r2
+}
+
+// JacobiConstant retorna C = 2Ω − v² para uma partícula no plano do
+// referencial girante normalizado. As curvas de velocidade zero de uma
+// partícula são os contornos 2Ω(x, y) = C.
+func JacobiConstant(mu float64, r, v Vec3) float64 {
+	return 2*EffectivePotential(mu, r.X, r.Y) - v.Dot(v)
+}
+
+// LagrangePoints devolve as posições heliocêntricas eclípticas (UA) de L1–L5
+// do par primário–secundário na data jd. O plano do referencial girante é
+// tirado da posição e da velocidade relativas do secundário (órbita osculante).
+func LagrangePoints(primary, secondary Body, jd float64) [5]Vec3 {
+	r1 := HeliocentricPosition(primary, jd)
+	r2 := HeliocentricPosition(secondary, jd)
+	v := HeliocentricVelocity(secondary, jd).Sub(HeliocentricVelocity(primary, jd))
+	return PairLagrangePoints(r1, r2, r2.Sub(r1).Cross(v), MassRatio(primary, secondary))
+}
+
+// PairLagrangePoints posiciona L1–L5 para dois corpos em r1 e r2 (primário e
+// secundário) com parâmetro de massa mu; normal é a direção do momento angular
+// da órbita, que define para que lado ficam L4 (à frente) e L5 (atrás).
+// Serve para qualquer unidade de distância.
+func PairLagrangePoints(r1, r2, normal Vec3, mu float64) [5]Vec3 {
+	r := r2.Sub(r1)
+	d := r.Norm()
+	ex := r.Unit()
+	ey := normal.Unit().Cross(ex)
+	bary := r1.Add(r.Scale(mu))
+
+	var out [5]Vec3
+	for i, p := range SynodicLagrangePoints(mu) {
+		out[i] = bary.Add(ex.Scale(p.X * d)).Add(ey.Scale(p.Y * d))
+	}
+	return out
+}
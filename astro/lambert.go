@@ -0,0 +1,103 @@
+package astro
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrLambert indica que o problema de Lambert não convergiu (por exemplo,
+// quando as duas posições são colineares com o corpo central).
+var ErrLambert = errors.New("astro: problema de Lambert sem solução")
+
+// stumpff devolve as funções de Stumpff C(z) e S(z) das variáveis universais.
+func stumpff(z float64) (c, s float64) {
+	switch {
+	case z > 1e-8:
+		sz := math.Sqrt(z)
+		return (1 - math.Cos(sz)) / z, (sz - math.Sin(sz)) / (sz * sz * sz)
+	case z < -1e-8:
+		sz := math.Sqrt(-z)
+		return (math.Cosh(sz) - 1) / -z, (math.Sinh(sz) - sz) / (sz * sz * sz)
+	}
+	return 1.0 / 2, 1.0 / 6
+}
+
+// Lambert resolve o problema de Lambert de revolução zero: a órbita em torno
+// de um corpo de parâmetro mu que vai de r1 a r2 no tempo tof. Devolve as
+// velocidades na partida e na chegada. Com prograde, a transferência segue o
+// sentido direto em torno do eixo z; senão, o retrógrado. Usa as variáveis
+// universais com bissecção (Vallado, algoritmo 58).
+func Lambert(r1, r2 Vec3, tof, mu float64, prograde bool) (v1, v2 Vec3, err error) {
+	n1, n2 := r1.Norm(), r2.Norm()
+	cosDnu := r1.Dot(r2) / (n1 * n2)
+	dnu := math.Acos(math.Max(-1, math.Min(1, cosDnu)))
+	if (r1.Cross(r2).Z < 0) == prograde {
+		dnu = 2*math.Pi - dnu
+	}
+	a := math.Sin(dnu) * math.Sqrt(n1*n2/(1-cosDnu))
+	if math.Abs(a) < 1e-12 || math.IsNaN(a) {
+		return Vec3{}, Vec3{}, ErrLambert
+	}
+
+	psi, low, high := 0.0, -4*math.Pi, 4*math.Pi*math.Pi
+	var y float64
+	for i := 0; i < 200; i++ {
+		c, s := stumpff(psi)
+		y = n1 + n2 + a*(psi*s-1)/math.Sqrt(c)
+		var t float64
+		if y < 0 {
+			// Fora do domínio: corresponde a tempos curtos demais
+			t = -1
+		} else {
+			chi := math.Sqrt(y / c)
+			t = (chi*chi*chi*s + a*math.Sqrt(y)) / math.Sqrt(mu)
+		}
+		if math.Abs(t-tof) < 1e-9*tof && y >= 0 {
+			f := 1 - y/n1
+			g := a * math.Sqrt(y/mu)
+			gdot := 1 - y/n2
+			v1 = r2.Sub(r1.Scale(f)).Scale(1 / g)
+			v2 = r2.Scale(gdot).Sub(r1).Scale(1 / g)
+			return v1, v2, nil
+		}
+		if t <= tof {
+			low = psi
+		} else {
+			high = psi
+		}
+		psi = (low + high) / 2
+	}
+	return Vec3{}, Vec3{}, ErrLambert
+}
+
+// Propagate avança o estado (r0, v0) de uma órbita kepleriana de parâmetro mu
+// por dt (que pode ser negativo), resolvendo a equação de Kepler universal.
+func Propagate(r0, v0 Vec3, dt, mu float64) (Vec3, Vec3) {
+	n0 := r0.Norm()
+	vr0 := r0.Dot(v0) / n0
+	alpha := 2/n0 - v0.Dot(v0)/mu
+	sqmu := math.Sqrt(mu)
+
+	chi := sqmu * math.Abs(alpha) * dt
+	for i := 0; i < 100; i++ {
+		z := alpha * chi * chi
+		c, s := stumpff(z)
+		f := n0*vr0/sqmu*chi*chi*c + (1-alpha*n0)*chi*chi*chi*s + n0*chi - sqmu*dt
+		df := n0*vr0/sqmu*chi*(1-z*s) + (1-alpha*n0)*chi*chi*c + n0
+		step := f / df
+		chi -= step
+		if math.Abs(step) < 1e-12 {
+			break
+		}
+	}
+
+	z := alpha * chi * chi
+	c, s := stumpff(z)
+	f := 1 - chi*chi/n0*c
+	g := dt - chi*chi*chi/sqmu*s
+	r := r0.Scale(f).Add(v0.Scale(g))
+	n := r.Norm()
+	fdot := sqmu / (n * n0) * (z*chi*s - chi)
+	gdot := 1 - chi*chi/n*c
+	return r, r0.Scale(fdot).Add(v0.Scale(gdot))
+}
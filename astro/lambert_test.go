@@ -0,0 +1,77 @@
+package astro
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLambert(t *testing.T) {
+	const muEarth = 398600.0 // km³/s²
+	tests := []struct {
+		name     string
+		r1, r2   Vec3
+		tof, mu  float64
+		prograde bool
+		v1, v2   Vec3 // velocidades esperadas; zero para só conferir com Propagate
+	}{
+		{
+			// Curtis, Orbital Mechanics for Engineering Students, exemplo 5.2
+			name: "curtis 5.2", r1: Vec3{5000, 10000, 2100}, r2: Vec3{-14600, 2500, 7000},
+			tof: 3600, mu: muEarth, prograde: true,
+			v1: Vec3{-5.9925, 1.9254, 3.2456}, v2: Vec3{-3.3125, -4.1966, -0.38529},
+		},
+		{name: "retrógrada", r1: Vec3{5000, 10000, 2100}, r2: Vec3{-14600, 2500, 7000}, tof: 3600, mu: muEarth},
+		{name: "mais de meia volta", r1: Vec3{7000, 0, 0}, r2: Vec3{-5000, -5000, 0}, tof: 4000, mu: muEarth, prograde: true},
+		{name: "terra a marte", r1: Vec3{1, 0, 0}, r2: Vec3{-1.2, 0.9, 0.03}, tof: 250, mu: GM(Sun), prograde: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v1, v2, err := Lambert(tt.r1, tt.r2, tt.tof, tt.mu, tt.prograde)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.v1 != (Vec3{}) {
+				if d := v1.Sub(tt.v1).Norm(); d > 1e-3 {
+					t.Errorf("v1 = %v, esperado %v", v1, tt.v1)
+				}
+				if d := v2.Sub(tt.v2).Norm(); d > 1e-3 {
+					t.Errorf("v2 = %v, esperado %v", v2, tt.v2)
+				}
+			}
+			// A órbita encontrada tem de levar r1 a r2 no tempo pedido
+			r, v := Propagate(tt.r1, v1, tt.tof, tt.mu)
+			if d := r.Sub(tt.r2).Norm(); d > 1e-6*tt.r2.Norm() {
+				t.Errorf("Propagate chega em %v, esperado %v", r, tt.r2)
+			}
+			if d := v.Sub(v2).Norm(); d > 1e-6*v2.Norm() {
+				t.Errorf("velocidade de chegada %v, Lambert deu %v", v, v2)
+			}
+			if got := isPrograde(tt.r1, v1); got != tt.prograde {
+				t.Errorf("sentido direto = %v, esperado %v", got, tt.prograde)
+			}
+		})
+	}
+}
+
+// isPrograde informa se o movimento em r com velocidade v é direto em torno de z.
+func isPrograde(r, v Vec3) bool {
+	return r.Cross(v).Z > 0
+}
+
+func TestLambertCollinear(t *testing.T) {
+	if _, _, err := Lambert(Vec3{1, 0, 0}, Vec3{2, 0, 0}, 100, GM(Sun), true); err != ErrLambert {
+		t.Errorf("posições colineares: err = %v, esperado ErrLambert", err)
+	}
+}
+
+func TestPropagateFullOrbit(t *testing.T) {
+	// Órbita circular: depois de um período o estado volta ao início
+	const mu = 398600.0
+	r0 := Vec3{7000, 0, 0}
+	v0 := Vec3{0, math.Sqrt(mu / 7000), 0}
+	period := 2 * math.Pi * math.Sqrt(7000*7000*7000/mu)
+	r, v := Propagate(r0, v0, period, mu)
+	if r.Sub(r0).Norm() > 1e-6 || v.Sub(v0).Norm() > 1e-9 {
+		t.Errorf("depois de um período: r = %v, v = %v", r, v)
+	}
+}
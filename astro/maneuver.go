@@ -0,0 +1,38 @@
+package astro
+
+import "math"
+
+// BurnVector converte um impulso dado nas direções da órbita (progrado ao
+// longo da velocidade, normal ao longo do momento angular e radial para fora,
+// perpendicular às outras duas) em um vetor nos eixos de r e v.
+func BurnVector(r, v Vec3, prograde, normal, radial float64) Vec3 {
+	t := v.Unit()
+	n := r.Cross(v).Unit()
+	out := t.Cross(n)
+	return t.Scale(prograde).Add(n.Scale(normal)).Add(out.Scale(radial))
+}
+
+// PropellantFor retorna a massa de propelente gasta para um impulso dv por uma
+// nave de massa total mass, pela equação do foguete de Tsiolkovsky.
+func PropellantFor(mass, dv, exhaustSpeed float64) float64 {
+	return mass * (1 - math.Exp(-dv/exhaustSpeed))
+}
+
+// DeltaVFor é a inversa de PropellantFor: o impulso obtido queimando a massa
+// de propelente burned.
+func DeltaVFor(mass, burned, exhaustSpeed float64) float64 {
+	return exhaustSpeed * math.Log(mass/(mass-burned))
+}
+
+// TurnAngle retorna o desvio da velocidade de excesso hiperbólico vInf de uma
+// passagem com periapse rp por um corpo de parâmetro mu: 2·asin(1/e).
+func TurnAngle(mu, rp, vInf float64) float64 {
+	e := 1 + rp*vInf*vInf/mu
+	return 2 * math.Asin(1/e)
+}
+
+// AimingRadius é o parâmetro de impacto que leva uma aproximação com
+// velocidade de excesso vInf à periapse rp.
+func AimingRadius(mu, rp, vInf float64) float64 {
+	return rp * math.Sqrt(1+2*mu/(rp*vInf*vInf))
+}
@@ -0,0 +1,56 @@
+package astro
+
+import "math"
+
+// EarthRadius é o raio equatorial da Terra em UA.
+const EarthRadius = 6378.137 / AU
+
+// lightTimePerAU é o tempo que a luz leva para percorrer 1 UA, em dias.
+const lightTimePerAU = 0.0057755183
+
+// Observer é um ponto da superfície da Terra.
+type Observer struct {
+	Lat float64 // latitude geográfica (radianos, norte positivo)
+	Lon float64 // longitude (radianos, leste positivo)
+}
+
+// LocalSiderealTime retorna o tempo sideral local (radianos).
+func (o Observer) LocalSiderealTime(jd float64) float64 {
+	return normalizeAngle(GMST(jd) + o.Lon)
+}
+
+// Position retorna a posição do observador relativa ao centro da Terra, em UA,
+// no referencial equatorial (Terra esférica).
+func (o Observer) Position(jd float64) Vec3 {
+	return SphericalToCartesian(o.LocalSiderealTime(jd), o.Lat).Scale(EarthRadius)
+}
+
+// Apparent retorna o vetor topocêntrico equatorial (UA) de um corpo visto pelo
+// observador, corrigido do tempo-luz. Aberração e nutação são desprezadas.
+func (o Observer) Apparent(b Body, jd float64) Vec3 {
+	earth := HeliocentricPosition(Earth, jd)
+	var geo Vec3
+	switch b {
+	case Moon:
+		geo = GeocentricMoon(jd)
+	default:
+		geo = HeliocentricPosition(b, jd).Sub(earth)
+		// Uma iteração de tempo-luz: onde o corpo estava quando a luz partiu
+		geo = HeliocentricPosition(b, jd-geo.Norm()*lightTimePerAU).Sub(earth)
+	}
+	return EclipticToEquatorial(geo).Sub(o.Position(jd))
+}
+
+// Horizontal converte um vetor equatorial (de qualquer módulo) em altura e
+// azimute (radianos; azimute a partir do norte, crescendo para leste).
+func (o Observer) Horizontal(eq Vec3, jd float64) (alt, az float64) {
+	ra, dec := CartesianToSpherical(eq)
+	h := o.LocalSiderealTime(jd) - ra
+	sinLat, cosLat := math.Sincos(o.Lat)
+	sinDec, cosDec := math.Sincos(dec)
+	sinH, cosH := math.Sincos(h)
+
+	alt = math.Asin(sinLat*sinDec + cosLat*cosDec*cosH)
+	az = math.Atan2(-cosDec*sinH, sinDec*cosLat-cosDec*sinLat*cosH)
+	return alt, normalizeAngle(az)
+}
@@ -0,0 +1,219 @@
+package astro
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// PhenomenonKind é o tipo de fenômeno planetário visto da Terra.
+type PhenomenonKind int
+
+const (
+	Conjunction         PhenomenonKind = iota // planeta exterior atrás do Sol
+	InferiorConjunction                       // Mercúrio ou Vênus entre a Terra e o Sol
+	SuperiorConjunction                       // Mercúrio ou Vênus atrás do Sol
+	Opposition
+	EasternElongation // maior elongação a leste (visível ao anoitecer)
+	WesternElongation // maior elongação a oeste (visível ao amanhecer)
+	RetrogradeStation // início do movimento retrógrado
+	DirectStation     // fim do movimento retrógrado
+)
+
+var phenomenonNames = [...]string{
+	"conjunção", "conjunção inferior", "conjunção superior", "oposição",
+	"maior elongação leste", "maior elongação oeste", "estação retrógrada", "estação direta",
+}
+
+func (k PhenomenonKind) String() string {
+	return phenomenonNames[k]
+}
+
+// Phenomenon é um evento encontrado por FindPhenomena. Elongation é a
+// separação angular entre o planeta e o Sol e Longitude a longitude
+// eclíptica geocêntrica do planeta, ambas em radianos.
+type Phenomenon struct {
+	Body       Body
+	Kind       PhenomenonKind
+	JD         float64
+	Elongation float64
+	Longitude  float64
+}
+
+// PhenomenaBodies são os corpos com fenômenos vistos da Terra.
+var PhenomenaBodies = []Body{Mercury, Venus, Mars, Jupiter, Saturn, Uranus, Neptune, Pluto}
+
+// geocentricPlanet é a posição do planeta vista do centro da Terra, corrigida
+// do tempo-luz.
+func geocentricPlanet(b Body, jd float64) Vec3 {
+	earth := HeliocentricPosition(Earth, jd)
+	geo := HeliocentricPosition(b, jd).Sub(earth)
+	return HeliocentricPosition(b, jd-geo.Norm()*lightTimePerAU).Sub(earth)
+}
+
+// Elongation devolve a separação angular entre o planeta e o Sol vista da
+// Terra, e a diferença de longitudes eclípticas planeta − Sol em (−π, π]:
+// positiva quando o planeta está a leste do Sol.
+func Elongation(b Body, jd float64) (sep, dlon float64) {
+	planet := geocentricPlanet(b, jd)
+	sun := HeliocentricPosition(Earth, jd).Scale(-1)
+	sep = math.Acos(math.Max(-1, math.Min(1, planet.Unit().Dot(sun.Unit()))))
+	lon, _ := CartesianToSpherical(planet)
+	sunLon, _ := CartesianToSpherical(sun)
+	return sep, wrapAngle(lon - sunLon)
+}
+
+// wrapAngle leva um ângulo para (−π, π].
+func wrapAngle(a float64) float64 {
+	a = normalizeAngle(a)
+	if a > math.Pi {
+		a -= 2 * math.Pi
+	}
+	return a
+}
+
+// longitudeRate é a variação diária da longitude geocêntrica do planeta,
+// por diferença centrada; negativa durante o movimento retrógrado.
+func longitudeRate(b Body, jd float64) float64 {
+	const h = 0.01
+	l1, _ := CartesianToSpherical(geocentricPlanet(b, jd-h))
+	l2, _ := CartesianToSpherical(geocentricPlanet(b, jd+h))
+	return wrapAngle(l2-l1) / (2 * h)
+}
+
+// findRoots devolve os zeros de f em [start, end], isolados pela troca de
+// sinal a cada step e refinados por bissecção.
+func findRoots(f func(float64) float64, start, end, step float64) []float64 {
+	var roots []float64
+	prev := f(start)
+	for t := start; t < end; t += step {
+		next := math.Min(t+step, end)
+		cur := f(next)
+		if (prev < 0) != (cur < 0) {
+			a, b, fa := t, next, prev
+			for b-a > 1e-6 {
+				mid := (a + b) / 2
+				if fm := f(mid); (fm < 0) == (fa < 0) {
+					a, fa = mid, fm
+				} else {
+					b = mid
+				}
+			}
+			roots = append(roots, (a+b)/2)
+		}
+		prev = cur
+	}
+	return roots
+}
+
+// FindPhenomena procura, entre as datas julianas start e end, as conjunções,
+// oposições, maiores elongações e estações do planeta b vistas da Terra.
+// Conjunções e oposições são os zeros do seno da diferença de longitudes;
+// as estações, os zeros da velocidade em longitude; as elongações, os
+// máximos da separação angular (só para Mercúrio e Vênus).
+func FindPhenomena(b Body, start, end float64) []Phenomenon {
+	const step = 1.0
+	inner := b == Mercury || b == Venus
+	var events []Phenomenon
+	add := func(kind PhenomenonKind, jd float64) {
+		sep, _ := Elongation(b, jd)
+		lon, _ := CartesianToSpherical(geocentricPlanet(b, jd))
+		events = append(events, Phenomenon{Body: b, Kind: kind, JD: jd, Elongation: sep, Longitude: lon})
+	}
+
+	sinElongation := func(jd float64) float64 { _, d := Elongation(b, jd); return math.Sin(d) }
+	for _, jd := range findRoots(sinElongation, start, end, step) {
+		sep, _ := Elongation(b, jd)
+		switch {
+		case sep > math.Pi/2:
+			add(Opposition, jd)
+		case !inner:
+			add(Conjunction, jd)
+		case geocentricPlanet(b, jd).Norm() < HeliocentricPosition(Earth, jd).Norm():
+			add(InferiorConjunction, jd)
+		default:
+			add(SuperiorConjunction, jd)
+		}
+	}
+
+	rate := func(jd float64) float64 { return longitudeRate(b, jd) }
+	for _, jd := range findRoots(rate, start, end, step) {
+		if rate(jd-step) > 0 {
+			add(RetrogradeStation, jd)
+		} else {
+			add(DirectStation, jd)
+		}
+	}
+
+	if inner {
+		negSep := func(jd float64) float64 { s, _ := Elongation(b, jd); return -s }
+		prev, cur := negSep(start), negSep(start+step)
+		for t := start + step; t+step <= end; t += step {
+			next := negSep(t + step)
+			if cur < prev && cur <= next {
+				jd := goldenMinimum(negSep, t-step, t+step)
+				if _, d := Elongation(b, jd); d > 0 {
+					add(EasternElongation, jd)
+				} else {
+					add(WesternElongation, jd)
+				}
+			}
+			prev, cur = cur, next
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].JD < events[j].JD })
+	return events
+}
+
+// phenomenaHeader são as colunas do CSV e as chaves do JSON dos fenômenos.
+var phenomenaHeader = []string{"data", "jd", "planeta", "evento", "elongacao_graus", "longitude_graus"}
+
+// phenomenonRecord formata um fenômeno nas colunas de phenomenaHeader.
+func phenomenonRecord(e Phenomenon) []string {
+	deg := func(v float64) string { return strconv.FormatFloat(v*180/math.Pi, 'f', 3, 64) }
+	return []string{
+		TimeFromJulian(e.JD).Format(time.RFC3339),
+		strconv.FormatFloat(e.JD, 'f', 5, 64),
+		e.Body.String(), e.Kind.String(), deg(e.Elongation), deg(e.Longitude),
+	}
+}
+
+// WritePhenomenaCSV grava os fenômenos em CSV, um por linha.
+func WritePhenomenaCSV(w io.Writer, events []Phenomenon) error {
+	out := csv.NewWriter(w)
+	out.Write(phenomenaHeader)
+	for _, e := range events {
+		out.Write(phenomenonRecord(e))
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WritePhenomenaJSON grava os fenômenos como uma lista JSON de objetos com
+// as mesmas chaves do CSV; os números saem como números.
+func WritePhenomenaJSON(w io.Writer, events []Phenomenon) error {
+	type record struct {
+		Date       string  `json:"data"`
+		JD         float64 `json:"jd"`
+		Planet     string  `json:"planeta"`
+		Event      string  `json:"evento"`
+		Elongation float64 `json:"elongacao_graus"`
+		Longitude  float64 `json:"longitude_graus"`
+	}
+	records := make([]record, 0, len(events))
+	for _, e := range events {
+		records = append(records, record{
+			Date: TimeFromJulian(e.JD).Format(time.RFC3339), JD: e.JD,
+			Planet: e.Body.String(), Event: e.Kind.String(),
+			Elongation: e.Elongation * 180 / math.Pi, Longitude: e.Longitude * 180 / math.Pi,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
@@ -0,0 +1,142 @@
+package astro
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// PorkchopCell é uma transferência direta entre dois planetas, com datas de
+// partida e chegada fixas, resolvida pelo problema de Lambert.
+type PorkchopCell struct {
+	From, To           Body
+	Departure, Arrival float64 // datas julianas
+	C3                 float64 // energia de lançamento, km²/s²
+	VInfDeparture      float64 // km/s
+	VInfArrival        float64 // km/s
+	Valid              bool
+}
+
+// DeltaV é a soma dos excessos hiperbólicos na partida e na chegada (km/s).
+func (c PorkchopCell) DeltaV() float64 {
+	return c.VInfDeparture + c.VInfArrival
+}
+
+// TransferOrbit devolve o estado heliocêntrico (UA, UA/dia) logo após a
+// partida da transferência direta de from para to entre as datas dadas.
+func TransferOrbit(from, to Body, departure, arrival float64) (r1, v1 Vec3, err error) {
+	if arrival <= departure {
+		return Vec3{}, Vec3{}, fmt.Errorf("astro: chegada antes da partida")
+	}
+	r1 = HeliocentricPosition(from, departure)
+	r2 := HeliocentricPosition(to, arrival)
+	v1, _, err = Lambert(r1, r2, arrival-departure, GM(Sun), true)
+	return r1, v1, err
+}
+
+// PorkchopTransfer resolve uma célula do porkchop: os excessos hiperbólicos
+// na partida e na chegada da transferência direta de from para to.
+func PorkchopTransfer(from, to Body, departure, arrival float64) PorkchopCell {
+	cell := PorkchopCell{From: from, To: to, Departure: departure, Arrival: arrival}
+	if arrival <= departure {
+		return cell
+	}
+	r1 := HeliocentricPosition(from, departure)
+	r2 := HeliocentricPosition(to, arrival)
+	v1, v2, err := Lambert(r1, r2, arrival-departure, GM(Sun), true)
+	if err != nil {
+		return cell
+	}
+	dep := v1.Sub(HeliocentricVelocity(from, departure)).Norm() * KmPerSecond
+	arr := v2.Sub(HeliocentricVelocity(to, arrival)).Norm() * KmPerSecond
+	cell.C3 = dep * dep
+	cell.VInfDeparture = dep
+	cell.VInfArrival = arr
+	cell.Valid = true
+	return cell
+}
+
+// Porkchop varre as datas de partida (linhas) e de chegada (colunas) a partir
+// de dep0 e arr0, por depDays e arrDays dias, com o passo step (dias).
+func Porkchop(from, to Body, dep0, depDays, arr0, arrDays, step float64) [][]PorkchopCell {
+	var grid [][]PorkchopCell
+	for d := 0.0; d <= depDays; d += step {
+		var row []PorkchopCell
+		for a := 0.0; a <= arrDays; a += step {
+			row = append(row, PorkchopTransfer(from, to, dep0+d, arr0+a))
+		}
+		grid = append(grid, row)
+	}
+	return grid
+}
+
+// porkchopHeader são as colunas do CSV do porkchop.
+var porkchopHeader = []string{"de", "para", "partida", "chegada", "voo_dias", "c3_km2s2", "vinf_partida_kms", "vinf_chegada_kms", "dv_total_kms"}
+
+// WritePorkchopCSV grava a grade em CSV, uma célula por linha; células sem
+// solução ficam com os campos numéricos vazios.
+func WritePorkchopCSV(w io.Writer, grid [][]PorkchopCell) error {
+	out := csv.NewWriter(w)
+	out.Write(porkchopHeader)
+	num := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+	for _, row := range grid {
+		for _, c := range row {
+			rec := []string{
+				c.From.String(), c.To.String(),
+				TimeFromJulian(c.Departure).Format(time.RFC3339),
+				TimeFromJulian(c.Arrival).Format(time.RFC3339),
+				num(c.Arrival - c.Departure), "", "", "", "",
+			}
+			if c.Valid {
+				rec[5], rec[6], rec[7], rec[8] = num(c.C3), num(c.VInfDeparture), num(c.VInfArrival), num(c.DeltaV())
+			}
+			out.Write(rec)
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// ReadPorkchopCSV lê uma grade gravada por WritePorkchopCSV, reagrupando as
+// células em linhas pela data de partida.
+func ReadPorkchopCSV(r io.Reader) ([][]PorkchopCell, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 || len(records[0]) != len(porkchopHeader) {
+		return nil, fmt.Errorf("astro: cabeçalho de porkchop inválido")
+	}
+	var grid [][]PorkchopCell
+	for i, rec := range records[1:] {
+		from, ok1 := BodyByName(rec[0])
+		to, ok2 := BodyByName(rec[1])
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("astro: linha %d: corpo desconhecido", i+2)
+		}
+		dep, err1 := time.Parse(time.RFC3339, rec[2])
+		arr, err2 := time.Parse(time.RFC3339, rec[3])
+		if err := errors.Join(err1, err2); err != nil {
+			return nil, fmt.Errorf("astro: linha %d: %w", i+2, err)
+		}
+		c := PorkchopCell{From: from, To: to, Departure: JulianDate(dep), Arrival: JulianDate(arr)}
+		if rec[5] != "" {
+			vals := make([]float64, 3)
+			for k, s := range rec[5:8] {
+				if vals[k], err = strconv.ParseFloat(s, 64); err != nil {
+					return nil, fmt.Errorf("astro: linha %d: %w", i+2, err)
+				}
+			}
+			c.C3, c.VInfDeparture, c.VInfArrival, c.Valid = vals[0], vals[1], vals[2], true
+		}
+		if n := len(grid); n == 0 || math.Abs(grid[n-1][0].Departure-c.Departure) > 1e-6 {
+			grid = append(grid, nil)
+		}
+		grid[len(grid)-1] = append(grid[len(grid)-1], c)
+	}
+	return grid, nil
+}
@@ -0,0 +1,50 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// J2000 é a data juliana da época J2000.0 (2000-01-01 12:00 TT).
+const J2000 = 2451545.0
+
+// unixEpochJD é a data juliana de 1970-01-01 00:00 UTC.
+const unixEpochJD = 2440587.5
+
+// JulianDate converte um instante para data juliana. A diferença entre UTC e TT
+// (pouco mais de um minuto) é desprezada.
+func JulianDate(t time.Time) float64 {
+	return unixEpochJD + float64(t.UTC().UnixNano())/(86400*1e9)
+}
+
+// TimeFromJulian é a inversa de JulianDate, em UTC.
+func TimeFromJulian(jd float64) time.Time {
+	ns := (jd - unixEpochJD) * 86400 * 1e9
+	return time.Unix(0, int64(ns)).UTC()
+}
+
+// centuries retorna o número de séculos julianos desde J2000.
+func centuries(jd float64) float64 {
+	return (jd - J2000) / 36525
+}
+
+// GMST retorna o tempo sideral médio de Greenwich (radianos, em [0, 2π)).
+func GMST(jd float64) float64 {
+	t := centuries(jd)
+	deg := 280.46061837 + 360.98564736629*(jd-J2000) + 0.000387933*t*t - t*t*t/38710000
+	return normalizeAngle(deg * math.Pi / 180)
+}
+
+// normalizeAngle leva um ângulo (radianos) para o intervalo [0, 2π).
+func normalizeAngle(a float64) float64 {
+	a = math.Mod(a, 2*math.Pi)
+	if a < 0 {
+		a += 2 * math.Pi
+	}
+	return a
+}
+
+// rad converte graus para radianos.
+func rad(deg float64) float64 {
+	return deg * math.Pi / 180
+}
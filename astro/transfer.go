@@ -0,0 +1,143 @@
+package astro
+
+import "math"
+
+// TransferLeg é meia elipse de uma transferência entre órbitas circulares
+// coplanares, do raio R1 ao raio R2.
+type TransferLeg struct {
+	R1, R2   float64
+	Start    float64 // ângulo inicial, medido a partir da direção de partida
+	Duration float64
+}
+
+// Transfer é uma manobra entre duas órbitas circulares coplanares em torno do
+// mesmo corpo central. As unidades seguem as de mu e dos raios (UA e dias com
+// GM(Sun), ou pixels e frames na cena).
+type Transfer struct {
+	Burns        []float64 // módulo de cada impulso
+	DeltaV       float64   // soma dos impulsos
+	TimeOfFlight float64
+	Sweep        float64 // ângulo percorrido em torno do corpo central
+	Legs         []TransferLeg
+}
+
+// Hohmann calcula a transferência de Hohmann entre as órbitas de raio r1 e r2.
+func Hohmann(mu, r1, r2 float64) Transfer {
+	leg := halfEllipse(mu, r1, r2, 0)
+	dv1 := math.Abs(visViva(mu, r1, (r1+r2)/2) - math.Sqrt(mu/r1))
+	dv2 := math.Abs(math.Sqrt(mu/r2) - visViva(mu, r2, (r1+r2)/2))
+	return Transfer{
+		Burns:        []float64{dv1, dv2},
+		DeltaV:       dv1 + dv2,
+		TimeOfFlight: leg.Duration,
+		Sweep:        math.Pi,
+		Legs:         []TransferLeg{leg},
+	}
+}
+
+// BiElliptic calcula a transferência bi-elíptica de r1 a r2 passando pelo raio
+// intermediário rb. Ela só economiza Δv em relação a Hohmann quando a razão
+// entre os raios passa de ~11,94 e rb é grande.
+func BiElliptic(mu, r1, r2, rb float64) Transfer {
+	a1, a2 := (r1+rb)/2, (r2+rb)/2
+	dv1 := math.Abs(visViva(mu, r1, a1) - math.Sqrt(mu/r1))
+	dv2 := math.Abs(visViva(mu, rb, a2) - visViva(mu, rb, a1))
+	dv3 := math.Abs(visViva(mu, r2, a2) - math.Sqrt(mu/r2))
+	out := halfEllipse(mu, r1, rb, 0)
+	back := halfEllipse(mu, rb, r2, math.Pi)
+	return Transfer{
+		Burns:        []float64{dv1, dv2, dv3},
+		DeltaV:       dv1 + dv2 + dv3,
+		TimeOfFlight: out.Duration + back.Duration,
+		Sweep:        2 * math.Pi,
+		Legs:         []TransferLeg{out, back},
+	}
+}
+
+// visViva é a velocidade orbital no raio r de uma órbita de semieixo maior a.
+func visViva(mu, r, a float64) float64 {
+	return math.Sqrt(mu * (2/r - 1/a))
+}
+
+// halfEllipse monta a meia elipse de r1 a r2 começando no ângulo start.
+func halfEllipse(mu, r1, r2, start float64) TransferLeg {
+	a := (r1 + r2) / 2
+	return TransferLeg{R1: r1, R2: r2, Start: start, Duration: math.Pi * math.Sqrt(a*a*a/mu)}
+}
+
+// PositionAt devolve o raio e o ângulo (a partir da direção de partida) da nave
+// no instante t desde a partida. Depois do fim, fica no ponto de chegada.
+func (tr Transfer) PositionAt(mu, t float64) (r, angle float64) {
+	for i, leg := range tr.Legs {
+		if t > leg.Duration && i < len(tr.Legs)-1 {
+			t -= leg.Duration
+			continue
+		}
+		return leg.positionAt(mu, math.Min(t, leg.Duration))
+	}
+	return 0, 0
+}
+
+// positionAt resolve a equação de Kepler ao longo da meia elipse.
+func (leg TransferLeg) positionAt(mu, t float64) (r, angle float64) {
+	a := (leg.R1 + leg.R2) / 2
+	e := math.Abs(leg.R2-leg.R1) / (leg.R1 + leg.R2)
+	m := t * math.Sqrt(mu/(a*a*a))
+	offset := 0.0
+	if leg.R1 > leg.R2 {
+		// Parte do apoastro: anomalia média começa em π
+		m += math.Pi
+		offset = -math.Pi
+	}
+	ecc := SolveKepler(m, e)
+	nu := 2 * math.Atan2(math.Sqrt(1+e)*math.Sin(ecc/2), math.Sqrt(1-e)*math.Cos(ecc/2))
+	if nu < 0 && leg.R1 > leg.R2 {
+		nu += 2 * math.Pi
+	}
+	return a * (1 - e*math.Cos(ecc)), leg.Start + nu + offset
+}
+
+// RequiredPhase é o ângulo de fase (alvo menos origem) que o alvo precisa ter
+// na partida para chegar ao ponto de encontro junto com a nave; n2 é o
+// movimento médio do alvo.
+func (tr Transfer) RequiredPhase(n2 float64) float64 {
+	return normalizeAngle(tr.Sweep - n2*tr.TimeOfFlight)
+}
+
+// WaitForPhase devolve quanto tempo falta para a fase passar de phase a
+// required, se ela varia a rate por unidade de tempo (n2 − n1).
+func WaitForPhase(phase, required, rate float64) float64 {
+	if rate == 0 {
+		return math.Inf(1)
+	}
+	if rate > 0 {
+		return normalizeAngle(required-phase) / rate
+	}
+	return normalizeAngle(phase-required) / -rate
+}
+
+// SemiMajorAxis retorna o semieixo maior da órbita heliocêntrica do planeta
+// (UA, em J2000); zero para o Sol e a Lua.
+func SemiMajorAxis(b Body) float64 {
+	return planetElements[b].a
+}
+
+// MeanMotion retorna o movimento médio (rad/dia) de uma órbita circular de
+// raio a (UA) em torno do Sol.
+func MeanMotion(a float64) float64 {
+	return math.Sqrt(GM(Sun) / (a * a * a))
+}
+
+// NextLaunchWindow devolve a data juliana da próxima janela de lançamento a
+// partir de jd para uma transferência de from para to calculada com GM(Sun),
+// usando as longitudes heliocêntricas reais e órbitas circulares médias.
+func NextLaunchWindow(from, to Body, tr Transfer, jd float64) float64 {
+	lon1, _ := CartesianToSpherical(HeliocentricPosition(from, jd))
+	lon2, _ := CartesianToSpherical(HeliocentricPosition(to, jd))
+	n1 := MeanMotion(SemiMajorAxis(from))
+	n2 := MeanMotion(SemiMajorAxis(to))
+	return jd + WaitForPhase(lon2-lon1, tr.RequiredPhase(n2), n2-n1)
+}
+
+// KmPerSecond converte velocidades de UA/dia para km/s.
+const KmPerSecond = AU / 86400
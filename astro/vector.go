@@ -0,0 +1,66 @@
+// Package astro reúne a matemática astronômica compartilhada pelos visualizadores:
+// vetores, sistemas de coordenadas e posições dos corpos do Sistema Solar.
+// Não depende de nenhuma biblioteca gráfica.
+package astro
+
+import "math"
+
+// Vec3 é um vetor cartesiano em precisão dupla.
+type Vec3 struct {
+	X, Y, Z float64
+}
+
+// Add soma dois vetores.
+func (v Vec3) Add(u Vec3) Vec3 {
+	return Vec3{v.X + u.X, v.Y + u.Y, v.Z + u.Z}
+}
+
+// Sub subtrai u de v.
+func (v Vec3) Sub(u Vec3) Vec3 {
+	return Vec3{v.X - u.X, v.Y - u.Y, v.Z - u.Z}
+}
+
+// Scale multiplica o vetor por um escalar.
+func (v Vec3) Scale(s float64) Vec3 {
+	return Vec3{v.X * s, v.Y * s, v.Z * s}
+}
+
+// Dot retorna o produto escalar.
+func (v Vec3) Dot(u Vec3) float64 {
+	return v.X*u.X + v.Y*u.Y + v.Z*u.Z
+}
+
+// Cross retorna o produto vetorial v × u.
+func (v Vec3) Cross(u Vec3) Vec3 {
+	return Vec3{
+		v.Y*u.Z - v.Z*u.Y,
+		v.Z*u.X - v.X*u.Z,
+		v.X*u.Y - v.Y*u.X,
+	}
+}
+
+// Norm retorna o módulo do vetor.
+func (v Vec3) Norm() float64 {
+	return math.Sqrt(v.Dot(v))
+}
+
+// Unit retorna o vetor normalizado (ou o vetor nulo, se o módulo for zero).
+func (v Vec3) Unit() Vec3 {
+	n := v.Norm()
+	if n == 0 {
+		return Vec3{}
+	}
+	return v.Scale(1 / n)
+}
+
+// RotateX gira o vetor de um ângulo (radianos) em torno do eixo X.
+func (v Vec3) RotateX(angle float64) Vec3 {
+	s, c := math.Sincos(angle)
+	return Vec3{v.X, c*v.Y - s*v.Z, s*v.Y + c*v.Z}
+}
+
+// RotateZ gira o vetor de um ângulo (radianos) em torno do eixo Z.
+func (v Vec3) RotateZ(angle float64) Vec3 {
+	s, c := math.Sincos(angle)
+	return Vec3{c*v.X - s*v.Y, s*v.X + c*v.Y, v.Z}
+}
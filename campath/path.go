@@ -0,0 +1,207 @@
+// Package campath descreve caminhos de câmera por keyframes, interpolados por
+// splines e sincronizados com o relógio da simulação, para gravar vídeos de
+// divulgação. Não depende de nenhuma biblioteca gráfica.
+package campath
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-playground/astro"
+)
+
+// Keyframe é uma pose da câmera no instante Time, em segundos do relógio da
+// simulação. Position e Target estão nas coordenadas da cena; Fovy é o campo
+// de visão vertical em graus.
+type Keyframe struct {
+	Time     float64
+	Position astro.Vec3
+	Target   astro.Vec3
+	Fovy     float64
+}
+
+// Path é um caminho de câmera por keyframes ordenados pelo tempo. Com Period
+// positivo, o caminho se repete a cada Period segundos, voltando do último
+// keyframe ao primeiro; sem ele, a câmera para no último.
+type Path struct {
+	Keys   []Keyframe
+	Period float64
+}
+
+// Add insere o keyframe mantendo a ordem dos tempos; um keyframe no mesmo
+// instante de outro o substitui.
+func (p *Path) Add(k Keyframe) {
+	i := sort.Search(len(p.Keys), func(i int) bool { return p.Keys[i].Time >= k.Time })
+	if i < len(p.Keys) && p.Keys[i].Time == k.Time {
+		p.Keys[i] = k
+		return
+	}
+	p.Keys = append(p.Keys, Keyframe{})
+	copy(p.Keys[i+1:], p.Keys[i:])
+	p.Keys[i] = k
+}
+
+// key devolve o i-ésimo keyframe estendido além das pontas: num caminho
+// periódico os índices dão a volta (com o tempo deslocado de Period); nos
+// outros, as pontas se repetem.
+func (p Path) key(i int) Keyframe {
+	n := len(p.Keys)
+	if p.Period <= 0 {
+		return p.Keys[max(0, min(i, n-1))]
+	}
+	turns := int(math.Floor(float64(i) / float64(n)))
+	k := p.Keys[i-turns*n]
+	k.Time += float64(turns) * p.Period
+	return k
+}
+
+// At devolve a pose da câmera no instante t, interpolada por uma spline de
+// Catmull-Rom, que passa por todos os keyframes com tangentes contínuas.
+func (p Path) At(t float64) Keyframe {
+	n := len(p.Keys)
+	if n == 0 {
+		return Keyframe{Time: t}
+	}
+	first := p.Keys[0].Time
+	if p.Period > 0 {
+		t = first + math.Mod(math.Mod(t-first, p.Period)+p.Period, p.Period)
+	} else {
+		t = math.Max(first, math.Min(t, p.Keys[n-1].Time))
+	}
+
+	// Trecho [key(i), key(i+1)] que contém t
+	i := sort.Search(n, func(i int) bool { return p.Keys[i].Time > t }) - 1
+	k0, k1, k2, k3 := p.key(i-1), p.key(i), p.key(i+1), p.key(i+2)
+	u := 0.0
+	if k2.Time > k1.Time {
+		u = (t - k1.Time) / (k2.Time - k1.Time)
+	}
+	return Keyframe{
+		Time:     t,
+		Position: catmullRom(k0.Position, k1.Position, k2.Position, k3.Position, u),
+		Target:   catmullRom(k0.Target, k1.Target, k2.Target, k3.Target, u),
+		Fovy:     catmullRom(astro.Vec3{X: k0.Fovy}, astro.Vec3{X: k1.Fovy}, astro.Vec3{X: k2.Fovy}, astro.Vec3{X: k3.Fovy}, u).X,
+	}
+}
+
+// catmullRom avalia a spline uniforme de Catmull-Rom entre p1 e p2 na
+// fração u.
+func catmullRom(p0, p1, p2, p3 astro.Vec3, u float64) astro.Vec3 {
+	u2, u3 := u*u, u*u*u
+	return p1.Scale(2).
+		Add(p2.Sub(p0).Scale(u)).
+		Add(p0.Scale(2).Sub(p1.Scale(5)).Add(p2.Scale(4)).Sub(p3).Scale(u2)).
+		Add(p1.Scale(3).Sub(p0).Sub(p2.Scale(3)).Add(p3).Scale(u3)).
+		Scale(0.5)
+}
+
+// Orbit cria um caminho que circula a origem no raio e na altura dados,
+// olhando para ela, com uma volta a cada period segundos (n keyframes).
+func Orbit(radius, height, period, fovy float64, n int) Path {
+	path := Path{Period: period}
+	for i := 0; i < n; i++ {
+		s, c := math.Sincos(2 * math.Pi * float64(i) / float64(n))
+		path.Keys = append(path.Keys, Keyframe{
+			Time:     period * float64(i) / float64(n),
+			Position: astro.Vec3{X: radius * c, Y: height, Z: radius * s},
+			Fovy:     fovy,
+		})
+	}
+	return path
+}
+
+// Load lê um caminho de câmera. Cada linha tem o formato
+//
+//	tempo  px py pz  ax ay az  fov
+//
+// com o tempo em segundos, a posição e o alvo na cena e o campo de visão em
+// graus; uma linha "loop período" torna o caminho periódico. Linhas vazias ou
+// iniciadas por '#' são ignoradas.
+func Load(path string) (Path, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Path{}, err
+	}
+	defer f.Close()
+
+	var p Path
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		var values []float64
+		for _, tok := range fields[min(1, len(fields)):] {
+			v, err := strconv.ParseFloat(tok, 64)
+			if err != nil {
+				return Path{}, fmt.Errorf("%s:%d: número inválido %q", path, lineNo, tok)
+			}
+			values = append(values, v)
+		}
+		if fields[0] == "loop" {
+			if len(values) != 1 || values[0] <= 0 {
+				return Path{}, fmt.Errorf("%s:%d: esperado \"loop período\" com período positivo", path, lineNo)
+			}
+			p.Period = values[0]
+			continue
+		}
+		t, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return Path{}, fmt.Errorf("%s:%d: tempo inválido %q", path, lineNo, fields[0])
+		}
+		if len(values) != 7 {
+			return Path{}, fmt.Errorf("%s:%d: esperado \"tempo px py pz ax ay az fov\"", path, lineNo)
+		}
+		p.Add(Keyframe{
+			Time:     t,
+			Position: astro.Vec3{X: values[0], Y: values[1], Z: values[2]},
+			Target:   astro.Vec3{X: values[3], Y: values[4], Z: values[5]},
+			Fovy:     values[6],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return Path{}, err
+	}
+	if err := p.Validate(); err != nil {
+		return Path{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Validate informa se o caminho pode ser gravado e lido de volta: num
+// caminho periódico, o último keyframe tem de vir antes do fim do período.
+func (p Path) Validate() error {
+	if p.Period > 0 && len(p.Keys) > 0 && p.Keys[len(p.Keys)-1].Time-p.Keys[0].Time >= p.Period {
+		return errors.New("o período do loop deve passar do último keyframe")
+	}
+	return nil
+}
+
+// Write grava o caminho no formato lido por Load; um caminho que Load
+// recusaria não é gravado.
+func (p Path) Write(w io.Writer) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	out := bufio.NewWriter(w)
+	fmt.Fprintln(out, "# tempo  px py pz  ax ay az  fov")
+	for _, k := range p.Keys {
+		fmt.Fprintf(out, "%.3f  %.2f %.2f %.2f  %.2f %.2f %.2f  %.2f\n", k.Time,
+			k.Position.X, k.Position.Y, k.Position.Z, k.Target.X, k.Target.Y, k.Target.Z, k.Fovy)
+	}
+	if p.Period > 0 {
+		fmt.Fprintf(out, "loop %.3f\n", p.Period)
+	}
+	return out.Flush()
+}
@@ -0,0 +1,112 @@
+package catalog
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go-playground/astro"
+)
+
+// Constellation é a figura ("stick figure") de uma constelação: uma ou mais
+// poligonais que ligam estrelas do catálogo pelos seus números HR.
+type Constellation struct {
+	Abbr  string  // abreviação IAU, por exemplo "Ori"
+	Name  string  // nome IAU, por exemplo "Orion"
+	Lines [][]int // poligonais de números HR
+}
+
+// LoadConstellations lê o arquivo de figuras. Cada linha tem o formato
+//
+//	Abr|Nome|HR-HR-HR HR-HR ...
+//
+// em que cada grupo separado por espaço é uma poligonal. Linhas vazias ou
+// iniciadas por '#' são ignoradas.
+func LoadConstellations(path string) ([]Constellation, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cons []Constellation
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.Split(line, "|")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("%s:%d: esperado \"Abr|Nome|traços\"", path, lineNo)
+		}
+		c := Constellation{
+			Abbr: strings.TrimSpace(parts[0]),
+			Name: strings.TrimSpace(parts[1]),
+		}
+		for _, poly := range strings.Fields(parts[2]) {
+			var hrs []int
+			for _, tok := range strings.Split(poly, "-") {
+				hr, err := strconv.Atoi(tok)
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: número HR inválido %q", path, lineNo, tok)
+				}
+				hrs = append(hrs, hr)
+			}
+			c.Lines = append(c.Lines, hrs)
+		}
+		cons = append(cons, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cons, nil
+}
+
+// IndexByHR indexa as estrelas pelo número HR.
+func IndexByHR(stars []Star) map[int]Star {
+	index := make(map[int]Star, len(stars))
+	for _, s := range stars {
+		index[s.HR] = s
+	}
+	return index
+}
+
+// Segments devolve os pares de estrelas ligados pela figura. Estrelas ausentes
+// do catálogo carregado são puladas, junto com os traços que as tocam.
+func (c Constellation) Segments(index map[int]Star) [][2]Star {
+	var segs [][2]Star
+	for _, poly := range c.Lines {
+		for i := 0; i+1 < len(poly); i++ {
+			a, okA := index[poly[i]]
+			b, okB := index[poly[i+1]]
+			if okA && okB {
+				segs = append(segs, [2]Star{a, b})
+			}
+		}
+	}
+	return segs
+}
+
+// Center retorna a direção média (equatorial J2000, unitária) das estrelas da
+// figura, usada para posicionar o rótulo. ok é falso se nenhuma estrela existir.
+func (c Constellation) Center(index map[int]Star) (dir astro.Vec3, ok bool) {
+	var sum astro.Vec3
+	n := 0
+	for _, poly := range c.Lines {
+		for _, hr := range poly {
+			if s, found := index[hr]; found {
+				sum = sum.Add(s.Direction())
+				n++
+			}
+		}
+	}
+	if n == 0 {
+		return astro.Vec3{}, false
+	}
+	return sum.Unit(), true
+}
@@ -0,0 +1,179 @@
+// Package catalog carrega catálogos de estrelas reais (Yale Bright Star
+// Catalogue) e as figuras das constelações para o fundo dos visualizadores.
+package catalog
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"go-playground/astro"
+)
+
+// Star é uma estrela do catálogo, com coordenadas equatoriais J2000.
+type Star struct {
+	HR   int     // número no Harvard Revised (BSC)
+	Name string  // designação de Bayer/Flamsteed, por exemplo "58Alp Ori"
+	RA   float64 // ascensão reta (radianos)
+	Dec  float64 // declinação (radianos)
+	Vmag float64 // magnitude visual
+	BV   float64 // índice de cor B–V
+}
+
+// LoadBSC lê um arquivo no formato de colunas fixas do BSC5 (arquivo "catalog"
+// do VizieR V/50). Linhas vazias ou iniciadas por '#' são ignoradas, assim como
+// as entradas sem posição J2000 (objetos removidos do catálogo original).
+func LoadBSC(path string) ([]Star, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var stars []Star
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.TrimSpace(field(line, 76, 90)) == "" {
+			continue
+		}
+		s, err := parseBSCLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		stars = append(stars, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return stars, nil
+}
+
+// parseBSCLine interpreta uma linha do BSC5 usando as colunas do ReadMe do VizieR.
+func parseBSCLine(line string) (Star, error) {
+	var s Star
+	var err error
+	if s.HR, err = strconv.Atoi(strings.TrimSpace(field(line, 1, 4))); err != nil {
+		return s, fmt.Errorf("HR inválido: %w", err)
+	}
+	s.Name = strings.TrimSpace(field(line, 5, 14))
+
+	rah, err1 := parseNumber(field(line, 76, 77))
+	ram, err2 := parseNumber(field(line, 78, 79))
+	ras, err3 := parseNumber(field(line, 80, 83))
+	ded, err4 := parseNumber(field(line, 85, 86))
+	dem, err5 := parseNumber(field(line, 87, 88))
+	des, err6 := parseNumber(field(line, 89, 90))
+	for _, e := range []error{err1, err2, err3, err4, err5, err6} {
+		if e != nil {
+			return s, fmt.Errorf("posição inválida: %w", e)
+		}
+	}
+	s.RA = (rah + ram/60 + ras/3600) * 15 * math.Pi / 180
+	s.Dec = (ded + dem/60 + des/3600) * math.Pi / 180
+	if field(line, 84, 84) == "-" {
+		s.Dec = -s.Dec
+	}
+
+	if s.Vmag, err = parseNumber(field(line, 103, 107)); err != nil {
+		return s, fmt.Errorf("magnitude inválida: %w", err)
+	}
+	// Algumas estrelas não têm B–V; nesse caso assume-se uma estrela branca.
+	if bv := strings.TrimSpace(field(line, 110, 114)); bv != "" {
+		if s.BV, err = strconv.ParseFloat(bv, 64); err != nil {
+			return s, fmt.Errorf("B-V inválido: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// field devolve as colunas [from, to] (base 1, inclusivas) de uma linha,
+// tolerando linhas truncadas à direita.
+func field(line string, from, to int) string {
+	if from > len(line) {
+		return ""
+	}
+	if to > len(line) {
+		to = len(line)
+	}
+	return line[from-1 : to]
+}
+
+func parseNumber(s string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(s), 64)
+}
+
+// Direction retorna o vetor unitário da estrela no referencial equatorial J2000.
+func (s Star) Direction() astro.Vec3 {
+	return astro.SphericalToCartesian(s.RA, s.Dec)
+}
+
+// Size devolve um fator de tamanho aparente: cerca de 1 para estrelas no limite
+// do olho nu (magnitude 6,5) e maior para as mais brilhantes.
+func (s Star) Size() float64 {
+	f := 1 + 0.35*(6.5-s.Vmag)
+	if f < 0.5 {
+		f = 0.5
+	}
+	return f
+}
+
+// Brightness converte a magnitude em um alfa entre 60 e 255.
+func (s Star) Brightness() uint8 {
+	b := 255 - (s.Vmag+1.5)*25
+	if b < 60 {
+		b = 60
+	} else if b > 255 {
+		b = 255
+	}
+	return uint8(b)
+}
+
+// Color estima a cor da estrela a partir do índice B–V: primeiro a temperatura
+// efetiva (fórmula de Ballesteros) e depois a cor aproximada de um corpo negro.
+func (s Star) Color() color.RGBA {
+	t := 4600 * (1/(0.92*s.BV+1.7) + 1/(0.92*s.BV+0.62))
+	r, g, b := blackbodyRGB(t)
+	return color.RGBA{r, g, b, s.Brightness()}
+}
+
+// blackbodyRGB aproxima a cor de um corpo negro à temperatura t (kelvin),
+// usando o ajuste de Tanner Helland.
+func blackbodyRGB(t float64) (uint8, uint8, uint8) {
+	t /= 100
+	var r, g, b float64
+	if t <= 66 {
+		r = 255
+		g = 99.4708025861*math.Log(t) - 161.1195681661
+	} else {
+		r = 329.698727446 * math.Pow(t-60, -0.1332047592)
+		g = 288.1221695283 * math.Pow(t-60, -0.0755148492)
+	}
+	switch {
+	case t >= 66:
+		b = 255
+	case t <= 19:
+		b = 0
+	default:
+		b = 138.5177312231*math.Log(t-10) - 305.0447927307
+	}
+	return clamp255(r), clamp255(g), clamp255(b)
+}
+
+func clamp255(v float64) uint8 {
+	if v < 0 {
+		return 0
+	} else if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
@@ -0,0 +1,99 @@
+// Comando lagrange imprime as posições dos pontos de Lagrange L1–L5 de um par
+// de corpos ao longo do tempo, em CSV, em qualquer referencial do pacote astro.
+//
+// Exemplo:
+//
+//	go run ./cmd/lagrange -par Sol-Terra -inicio 2025-01-01T00:00:00Z -dias 365 -passo 30
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-playground/astro"
+)
+
+func main() {
+	pair := flag.String("par", "Sol-Terra", "par primário-secundário (ex.: Terra-Lua, Sol-Jupiter)")
+	start := flag.String("inicio", "", "data inicial em RFC 3339 (padrão: agora)")
+	days := flag.Float64("dias", 0, "intervalo coberto, em dias")
+	step := flag.Float64("passo", 1, "passo entre linhas, em dias")
+	frameName := flag.String("referencial", "helio", "referencial: helio, bari, geo ou o nome de um corpo")
+	km := flag.Bool("km", false, "posições em km em vez de UA")
+	flag.Parse()
+
+	primary, secondary, err := parsePair(*pair)
+	if err != nil {
+		log.Fatal(err)
+	}
+	frame, err := astro.ParseFrame(*frameName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	t0 := time.Now().UTC()
+	if *start != "" {
+		if t0, err = time.Parse(time.RFC3339, *start); err != nil {
+			log.Fatalf("data inválida: %v", err)
+		}
+	}
+	if *step <= 0 {
+		log.Fatal("o passo deve ser positivo")
+	}
+
+	scale, unit := 1.0, "ua"
+	if *km {
+		scale, unit = astro.AU, "km"
+	}
+
+	w := csv.NewWriter(os.Stdout)
+	header := []string{"data", "jd"}
+	for _, name := range astro.LagrangeNames {
+		for _, axis := range []string{"x", "y", "z"} {
+			header = append(header, name+"_"+axis+"_"+unit)
+		}
+	}
+	w.Write(header)
+
+	jd0 := astro.JulianDate(t0)
+	for d := 0.0; d <= *days; d += *step {
+		jd := jd0 + d
+		row := []string{astro.TimeFromJulian(jd).Format(time.RFC3339), strconv.FormatFloat(jd, 'f', 5, 64)}
+		for _, p := range astro.LagrangePoints(primary, secondary, jd) {
+			p = frame.FromHeliocentric(p, jd).Scale(scale)
+			for _, c := range []float64{p.X, p.Y, p.Z} {
+				row = append(row, strconv.FormatFloat(c, 'g', 10, 64))
+			}
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parsePair interpreta "Primário-Secundário".
+func parsePair(s string) (astro.Body, astro.Body, error) {
+	names := strings.Split(s, "-")
+	if len(names) != 2 {
+		return 0, 0, fmt.Errorf("par inválido %q: use Primário-Secundário", s)
+	}
+	primary, ok := astro.BodyByName(names[0])
+	if !ok {
+		return 0, 0, fmt.Errorf("corpo desconhecido: %q", names[0])
+	}
+	secondary, ok := astro.BodyByName(names[1])
+	if !ok {
+		return 0, 0, fmt.Errorf("corpo desconhecido: %q", names[1])
+	}
+	if primary == secondary {
+		return 0, 0, fmt.Errorf("o par precisa de dois corpos diferentes")
+	}
+	return primary, secondary, nil
+}
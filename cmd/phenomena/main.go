@@ -0,0 +1,79 @@
+// Comando phenomena lista as conjunções, oposições, maiores elongações e
+// estações dos planetas vistas da Terra num intervalo de datas, em CSV ou JSON.
+//
+// Exemplo:
+//
+//	go run ./cmd/phenomena -inicio 2025-01-01T00:00:00Z -dias 365 -planetas Marte,Jupiter -formato json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go-playground/astro"
+)
+
+func main() {
+	start := flag.String("inicio", "", "data inicial em RFC 3339 (padrão: agora)")
+	days := flag.Float64("dias", 365, "intervalo coberto, em dias")
+	planets := flag.String("planetas", "", "planetas separados por vírgula (padrão: todos)")
+	format := flag.String("formato", "csv", "formato de saída: csv ou json")
+	flag.Parse()
+
+	bodies, err := parseBodies(*planets)
+	if err != nil {
+		log.Fatal(err)
+	}
+	t0 := time.Now().UTC()
+	if *start != "" {
+		if t0, err = time.Parse(time.RFC3339, *start); err != nil {
+			log.Fatalf("data inválida: %v", err)
+		}
+	}
+	if *days <= 0 {
+		log.Fatal("o intervalo deve ser positivo")
+	}
+
+	jd0 := astro.JulianDate(t0)
+	var events []astro.Phenomenon
+	for _, b := range bodies {
+		events = append(events, astro.FindPhenomena(b, jd0, jd0+*days)...)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].JD < events[j].JD })
+
+	switch *format {
+	case "csv":
+		err = astro.WritePhenomenaCSV(os.Stdout, events)
+	case "json":
+		err = astro.WritePhenomenaJSON(os.Stdout, events)
+	default:
+		err = fmt.Errorf("formato desconhecido: %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseBodies interpreta a lista de planetas; vazia significa todos.
+func parseBodies(s string) ([]astro.Body, error) {
+	if s == "" {
+		return astro.PhenomenaBodies, nil
+	}
+	var bodies []astro.Body
+	for _, name := range strings.Split(s, ",") {
+		b, ok := astro.BodyByName(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("corpo desconhecido: %q", name)
+		}
+		if b == astro.Sun || b == astro.Earth || b == astro.Moon {
+			return nil, fmt.Errorf("%s não tem fenômenos vistos da Terra", b)
+		}
+		bodies = append(bodies, b)
+	}
+	return bodies, nil
+}
@@ -0,0 +1,206 @@
+// Comando porkchop varre datas de partida e de chegada entre dois planetas,
+// resolve o problema de Lambert em cada par de datas e grava a grade de C3 e
+// Δv em CSV e como um gráfico de contorno em PNG (partida no eixo horizontal,
+// chegada no vertical, marcas a cada 30 dias).
+//
+// Exemplo:
+//
+//	go run ./cmd/porkchop -de Terra -para Marte -partida 2026-08-01T00:00:00Z \
+//		-partida-dias 180 -chegada 2027-03-01T00:00:00Z -chegada-dias 300 \
+//		-csv porkchop.csv -png porkchop.png
+//
+// O CSV pode ser aberto no visualizador 3D com -porkchop porkchop.csv.
+package main
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+	"math"
+	"os"
+	"time"
+
+	"go-playground/astro"
+)
+
+func main() {
+	fromName := flag.String("de", "Terra", "planeta de partida")
+	toName := flag.String("para", "Marte", "planeta de chegada")
+	depStart := flag.String("partida", "", "primeira data de partida em RFC 3339 (padrão: agora)")
+	depDays := flag.Float64("partida-dias", 180, "intervalo de datas de partida, em dias")
+	arrStart := flag.String("chegada", "", "primeira data de chegada em RFC 3339 (padrão: partida + 120 dias)")
+	arrDays := flag.Float64("chegada-dias", 300, "intervalo de datas de chegada, em dias")
+	step := flag.Float64("passo", 2, "passo da grade, em dias")
+	csvPath := flag.String("csv", "porkchop.csv", "arquivo CSV de saída (vazio para não gravar)")
+	pngPath := flag.String("png", "porkchop.png", "arquivo PNG de saída (vazio para não gravar)")
+	c3Max := flag.Float64("c3-max", 50, "C3 (km²/s²) do topo da escala de cores")
+	level := flag.Float64("nivel", 5, "espaçamento das curvas de nível de C3 (km²/s²)")
+	flag.Parse()
+
+	from, ok := astro.BodyByName(*fromName)
+	if !ok {
+		log.Fatalf("corpo desconhecido: %q", *fromName)
+	}
+	to, ok := astro.BodyByName(*toName)
+	if !ok {
+		log.Fatalf("corpo desconhecido: %q", *toName)
+	}
+	if *step <= 0 {
+		log.Fatal("o passo deve ser positivo")
+	}
+	if *depDays < 0 || *arrDays < 0 {
+		log.Fatal("os intervalos -partida-dias e -chegada-dias não podem ser negativos")
+	}
+	dep0 := astro.JulianDate(parseDate(*depStart, time.Now().UTC()))
+	arr0 := dep0 + 120
+	if *arrStart != "" {
+		arr0 = astro.JulianDate(parseDate(*arrStart, time.Time{}))
+	}
+
+	grid := astro.Porkchop(from, to, dep0, *depDays, arr0, *arrDays, *step)
+
+	if *csvPath != "" {
+		f, err := os.Create(*csvPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := astro.WritePorkchopCSV(f, grid); err != nil {
+			log.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *pngPath != "" {
+		f, err := os.Create(*pngPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := png.Encode(f, render(grid, *step, *c3Max, *level)); err != nil {
+			log.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if best, ok := minimum(grid); ok {
+		log.Printf("menor C3: %.2f km²/s² partindo em %s, chegando em %s (%.0f dias, Δv %.2f km/s)",
+			best.C3, astro.TimeFromJulian(best.Departure).Format("2006-01-02"),
+			astro.TimeFromJulian(best.Arrival).Format("2006-01-02"), best.Arrival-best.Departure, best.DeltaV())
+	}
+}
+
+func parseDate(s string, def time.Time) time.Time {
+	if s == "" {
+		return def
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		log.Fatalf("data inválida %q: %v", s, err)
+	}
+	return t
+}
+
+// minimum devolve a célula de menor C3.
+func minimum(grid [][]astro.PorkchopCell) (astro.PorkchopCell, bool) {
+	var best astro.PorkchopCell
+	found := false
+	for _, row := range grid {
+		for _, c := range row {
+			if c.Valid && (!found || c.C3 < best.C3) {
+				best, found = c, true
+			}
+		}
+	}
+	return best, found
+}
+
+// Dimensões do gráfico: pixels por célula e margem para as marcas dos eixos.
+const (
+	cellPixels = 4
+	margin     = 12
+)
+
+// render desenha o mapa de cores de C3 com curvas de nível a cada level.
+func render(grid [][]astro.PorkchopCell, step, c3Max, level float64) image.Image {
+	cols, rows := len(grid), len(grid[0])
+	w, h := (cols-1)*cellPixels+1, (rows-1)*cellPixels+1
+	img := image.NewRGBA(image.Rect(0, 0, w+2*margin, h+2*margin))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+
+	// C3 interpolado (bilinear) em cada pixel; sem solução vale c3Max
+	value := func(i, j int) float64 {
+		c := grid[i][j]
+		if !c.Valid {
+			return c3Max
+		}
+		return math.Min(c.C3, c3Max)
+	}
+	field := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			fx, fy := float64(x)/cellPixels, float64(y)/cellPixels
+			i, j := min(int(fx), cols-2), min(int(fy), rows-2)
+			if cols == 1 {
+				i = 0
+			}
+			if rows == 1 {
+				j = 0
+			}
+			tx, ty := fx-float64(i), fy-float64(j)
+			v := value(i, j)
+			if cols > 1 && rows > 1 {
+				v = (1-tx)*(1-ty)*value(i, j) + tx*(1-ty)*value(i+1, j) +
+					(1-tx)*ty*value(i, j+1) + tx*ty*value(i+1, j+1)
+			}
+			field[y*w+x] = v
+		}
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := field[y*w+x]
+			clr := colormap(v / c3Max)
+			band := math.Floor(v / level)
+			edge := (x+1 < w && math.Floor(field[y*w+x+1]/level) != band) ||
+				(y+1 < h && math.Floor(field[(y+1)*w+x]/level) != band)
+			if edge && v < c3Max {
+				clr = color.RGBA{20, 20, 20, 255}
+			}
+			// A chegada cresce para cima
+			img.Set(margin+x, margin+h-1-y, clr)
+		}
+	}
+
+	// Marcas a cada 30 dias nos dois eixos
+	tick := color.RGBA{0, 0, 0, 255}
+	every := int(math.Max(1, math.Round(30/step))) * cellPixels
+	for x := 0; x < w; x += every {
+		for k := 1; k <= margin/2; k++ {
+			img.Set(margin+x, margin+h+k, tick)
+		}
+	}
+	for y := 0; y < h; y += every {
+		for k := 1; k <= margin/2; k++ {
+			img.Set(margin-k, margin+h-1-y, tick)
+		}
+	}
+	return img
+}
+
+// colormap vai de azul (t = 0) a verde, amarelo e vermelho (t = 1).
+func colormap(t float64) color.RGBA {
+	t = math.Max(0, math.Min(1, t))
+	stops := []color.RGBA{{40, 60, 200, 255}, {40, 190, 90, 255}, {240, 220, 60, 255}, {220, 50, 40, 255}}
+	f := t * float64(len(stops)-1)
+	i := min(int(f), len(stops)-2)
+	u := f - float64(i)
+	a, b := stops[i], stops[i+1]
+	mix := func(p, q uint8) uint8 { return uint8(float64(p) + u*(float64(q)-float64(p))) }
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 255}
+}
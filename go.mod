@@ -2,13 +2,15 @@ module go-playground
 
 go 1.23
 
-require github.com/hajimehoshi/ebiten/v2 v2.8.6
+require (
+	github.com/gen2brain/raylib-go/raylib v0.0.0-20250109172833-6dbba4f81a9b
+	github.com/hajimehoshi/ebiten/v2 v2.8.6
+)
 
 require (
 	github.com/ebitengine/gomobile v0.0.0-20240911145611-4856209ac325 // indirect
 	github.com/ebitengine/hideconsole v1.0.0 // indirect
 	github.com/ebitengine/purego v0.8.2 // indirect
-	github.com/gen2brain/raylib-go/raylib v0.0.0-20250109172833-6dbba4f81a9b // indirect
 	github.com/jezek/xgb v1.1.1 // indirect
 	golang.org/x/exp v0.0.0-20250128182459-e0ece0dbea4c // indirect
 	golang.org/x/sync v0.10.0 // indirect
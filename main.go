@@ -1,8 +1,16 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"go-playground/astro"
+	"go-playground/catalog"
+	"go-playground/recorder"
+	"go-playground/trail"
+	"image"
 	"image/color"
 	"log"
 	"math"
@@ -10,13 +18,18 @@ import (
 	"time"
 )
 
+// Catálogo de estrelas (formato BSC5) e figuras das constelações usados no fundo.
+const (
+	starCatalogFile   = "data/bsc5.dat"
+	constellationFile = "data/constellations.txt"
+)
+
 // dummyImage é utilizada como textura para desenhar triângulos.
 var dummyImage *ebiten.Image
 
 func init() {
 	dummyImage = ebiten.NewImage(1, 1)
 	dummyImage.Fill(color.White)
-	rand.Seed(time.Now().UnixNano())
 }
 
 // lerpColor interpola linearmente entre duas cores.
@@ -29,98 +42,178 @@ func lerpColor(c1, c2 color.RGBA, t float64) color.RGBA {
 	}
 }
 
-// drawFilledCircle desenha um círculo preenchido aproximando-o por um fan de triângulos.
-func drawFilledCircle(screen *ebiten.Image, cx, cy, radius float64, clr color.RGBA) {
-	const segments = 30
-	n := segments
+// -------------------------
+// Batch de geometria
+// -------------------------
+
+// batch acumula os triângulos de um frame e os envia à tela em poucas
+// chamadas de DrawTriangles. Toda a geometria usa a mesma textura (dummyImage)
+// e, na maior parte do frame, o mesmo alvo e o mesmo blend; a batch só é
+// descarregada quando eles mudam (use), quando os índices uint16 chegariam ao
+// limite (maxBatchVertices) e no fim do frame. Os textos
+// são guardados e desenhados por cima da geometria no flush.
+type batch struct {
+	target   *ebiten.Image
+	blend    ebiten.Blend
+	cam      *camera2D // nil desenha direto em pixels da tela
+	vertices []ebiten.Vertex
+	indices  []uint16
+	labels   []label
+}
+
+// maxBatchVertices é o número de vértices endereçáveis por índices uint16.
+const maxBatchVertices = math.MaxUint16 + 1
+
+// label é um texto de depuração a desenhar depois da geometria.
+type label struct {
+	text string
+	x, y int
+}
+
+// begin prepara a batch para um novo frame, reaproveitando os buffers.
+func (b *batch) begin(target *ebiten.Image) {
+	b.target = target
+	b.blend = ebiten.Blend{}
+	b.cam = nil
+	b.vertices = b.vertices[:0]
+	b.indices = b.indices[:0]
+	b.labels = b.labels[:0]
+}
+
+// use troca o alvo e o blend dos próximos triângulos, descarregando antes os
+// que foram acumulados com o estado anterior.
+func (b *batch) use(target *ebiten.Image, blend ebiten.Blend) {
+	if target != b.target || blend != b.blend {
+		b.flushTriangles()
+		b.target, b.blend = target, blend
+	}
+}
+
+// reserve garante espaço para n vértices, descarregando a geometria
+// acumulada se preciso, e devolve o índice do primeiro deles.
+func (b *batch) reserve(n int) uint16 {
+	if len(b.vertices)+n > maxBatchVertices {
+		b.flushTriangles()
+	}
+	return uint16(len(b.vertices))
+}
+
+// vertex acrescenta um vértice de cor sólida.
+func (b *batch) vertex(x, y float64, clr color.RGBA) {
+	b.vertices = append(b.vertices, ebiten.Vertex{
+		DstX: float32(x), DstY: float32(y),
+		ColorR: float32(clr.R) / 255, ColorG: float32(clr.G) / 255,
+		ColorB: float32(clr.B) / 255, ColorA: float32(clr.A) / 255,
+	})
+}
+
+// print agenda um texto de depuração na posição dada.
+func (b *batch) print(text string, x, y int) {
+	b.labels = append(b.labels, label{text, x, y})
+}
+
+func (b *batch) flushTriangles() {
+	if len(b.indices) > 0 {
+		b.target.DrawTriangles(b.vertices, b.indices, dummyImage, &ebiten.DrawTrianglesOptions{Blend: b.blend})
+	}
+	b.vertices = b.vertices[:0]
+	b.indices = b.indices[:0]
+}
 
-	vertices := make([]ebiten.Vertex, n+2)
-	rf, gf, bf, af := float32(clr.R)/255, float32(clr.G)/255, float32(clr.B)/255, float32(clr.A)/255
+// flush desenha a geometria pendente e, por cima, os textos.
+func (b *batch) flush() {
+	b.flushTriangles()
+	for _, l := range b.labels {
+		ebitenutil.DebugPrintAt(b.target, l.text, l.x, l.y)
+	}
+	b.labels = b.labels[:0]
+}
 
-	// Centro do círculo.
-	vertices[0] = ebiten.Vertex{
-		DstX:   float32(cx),
-		DstY:   float32(cy),
-		ColorR: rf, ColorG: gf, ColorB: bf, ColorA: af,
-		SrcX: 0, SrcY: 0,
+// point leva um ponto do mundo para a tela pela câmera da batch.
+func (b *batch) point(x, y float64) (float64, float64) {
+	if b.cam == nil {
+		return x, y
 	}
+	return b.cam.toScreen(x, y)
+}
+
+// length converte uma distância do mundo em pixels.
+func (b *batch) length(d float64) float64 {
+	if b.cam == nil {
+		return d
+	}
+	return d * b.cam.zoom
+}
 
-	// Vértices do contorno.
+// circleSegments escolhe quantos segmentos aproximam um círculo: poucos para
+// estrelas e asteroides de um ou dois pixels, até 30 para os grandes.
+func circleSegments(radius float64) int {
+	return int(math.Max(8, math.Min(30, radius*4)))
+}
+
+// drawFilledCircle desenha um círculo preenchido aproximando-o por um fan de triângulos.
+func drawFilledCircle(b *batch, cx, cy, radius float64, clr color.RGBA) {
+	cx, cy = b.point(cx, cy)
+	radius = b.length(radius)
+	n := circleSegments(radius)
+	base := b.reserve(n + 2)
+
+	// Centro do círculo e vértices do contorno.
+	b.vertex(cx, cy, clr)
 	for i := 0; i <= n; i++ {
 		theta := 2 * math.Pi * float64(i) / float64(n)
-		x := cx + radius*math.Cos(theta)
-		y := cy + radius*math.Sin(theta)
-		vertices[i+1] = ebiten.Vertex{
-			DstX:   float32(x),
-			DstY:   float32(y),
-			ColorR: rf, ColorG: gf, ColorB: bf, ColorA: af,
-			SrcX: 0, SrcY: 0,
-		}
+		b.vertex(cx+radius*math.Cos(theta), cy+radius*math.Sin(theta), clr)
 	}
-
-	indices := make([]uint16, n*3)
 	for i := 0; i < n; i++ {
-		indices[i*3] = 0
-		indices[i*3+1] = uint16(i + 1)
-		indices[i*3+2] = uint16(i + 2)
+		b.indices = append(b.indices, base, base+uint16(i+1), base+uint16(i+2))
 	}
-	screen.DrawTriangles(vertices, indices, dummyImage, nil)
 }
 
-// drawThickLine desenha uma linha grossa entre dois pontos.
-func drawThickLine(screen *ebiten.Image, x1, y1, x2, y2, thickness float64, clr color.RGBA) {
+// drawThickLine desenha uma linha grossa entre dois pontos; a espessura é em
+// pixels, qualquer que seja o zoom.
+func drawThickLine(b *batch, x1, y1, x2, y2, thickness float64, clr color.RGBA) {
+	x1, y1 = b.point(x1, y1)
+	x2, y2 = b.point(x2, y2)
 	dx := x2 - x1
 	dy := y2 - y1
 	length := math.Hypot(dx, dy)
 	if length == 0 {
 		return
 	}
-	nx := -dy / length
-	ny := dx / length
-	half := thickness / 2
-
-	x1a, y1a := x1+nx*half, y1+ny*half
-	x1b, y1b := x1-nx*half, y1-ny*half
-	x2a, y2a := x2+nx*half, y2+ny*half
-	x2b, y2b := x2-nx*half, y2-ny*half
+	nx := -dy / length * thickness / 2
+	ny := dx / length * thickness / 2
 
-	rf, gf, bf, af := float32(clr.R)/255, float32(clr.G)/255, float32(clr.B)/255, float32(clr.A)/255
-	vertices := []ebiten.Vertex{
-		{DstX: float32(x1a), DstY: float32(y1a), SrcX: 0, SrcY: 0, ColorR: rf, ColorG: gf, ColorB: bf, ColorA: af},
-		{DstX: float32(x2a), DstY: float32(y2a), SrcX: 0, SrcY: 0, ColorR: rf, ColorG: gf, ColorB: bf, ColorA: af},
-		{DstX: float32(x2b), DstY: float32(y2b), SrcX: 0, SrcY: 0, ColorR: rf, ColorG: gf, ColorB: bf, ColorA: af},
-		{DstX: float32(x1b), DstY: float32(y1b), SrcX: 0, SrcY: 0, ColorR: rf, ColorG: gf, ColorB: bf, ColorA: af},
-	}
-	indices := []uint16{0, 1, 2, 0, 2, 3}
-	screen.DrawTriangles(vertices, indices, dummyImage, nil)
+	base := b.reserve(4)
+	b.vertex(x1+nx, y1+ny, clr)
+	b.vertex(x2+nx, y2+ny, clr)
+	b.vertex(x2-nx, y2-ny, clr)
+	b.vertex(x1-nx, y1-ny, clr)
+	b.indices = append(b.indices, base, base+1, base+2, base, base+2, base+3)
 }
 
 // drawGlowingLine desenha uma linha com três camadas (espessuras e cores diferentes).
-func drawGlowingLine(screen *ebiten.Image, x1, y1, x2, y2 float64,
+func drawGlowingLine(b *batch, x1, y1, x2, y2 float64,
 	glowColor, midColor, coreColor color.RGBA) {
-	drawThickLine(screen, x1, y1, x2, y2, 4, glowColor)
-	drawThickLine(screen, x1, y1, x2, y2, 2, midColor)
-	drawThickLine(screen, x1, y1, x2, y2, 1, coreColor)
+	drawThickLine(b, x1, y1, x2, y2, 4, glowColor)
+	drawThickLine(b, x1, y1, x2, y2, 2, midColor)
+	drawThickLine(b, x1, y1, x2, y2, 1, coreColor)
 }
 
 // drawCircleOutline desenha o contorno de um círculo, aproximado por segmentos.
-func drawCircleOutline(screen *ebiten.Image, cx, cy, radius, thickness float64, clr color.RGBA) {
+func drawCircleOutline(b *batch, cx, cy, radius, thickness float64, clr color.RGBA) {
 	const segments = 60
-	points := make([][2]float64, segments)
-	for i := 0; i < segments; i++ {
+	px, py := cx+radius, cy
+	for i := 1; i <= segments; i++ {
 		theta := 2 * math.Pi * float64(i) / float64(segments)
 		x := cx + radius*math.Cos(theta)
 		y := cy + radius*math.Sin(theta)
-		points[i] = [2]float64{x, y}
-	}
-	for i := 0; i < segments; i++ {
-		next := (i + 1) % segments
-		drawThickLine(screen, points[i][0], points[i][1], points[next][0], points[next][1], thickness, clr)
+		drawThickLine(b, px, py, x, y, thickness, clr)
+		px, py = x, y
 	}
 }
 
 // drawSunGradient desenha o sol com gradiente radial e pulsação.
-func drawSunGradient(screen *ebiten.Image, cx, cy, baseRadius, t float64) {
+func drawSunGradient(b *batch, cx, cy, baseRadius, t float64) {
 	steps := 30
 	// O sol pulsa levemente (variação de ±10% no raio).
 	pulse := 1 + 0.1*math.Sin(t*2)
@@ -140,23 +233,23 @@ func drawSunGradient(screen *ebiten.Image, cx, cy, baseRadius, t float64) {
 		} else {
 			clr = lerpColor(midColor, yellow, (f-0.3)/0.7)
 		}
-		drawFilledCircle(screen, cx, cy, r, clr)
+		drawFilledCircle(b, cx, cy, r, clr)
 	}
 }
 
 // drawPlanetGradient desenha um planeta com gradiente do contorno (cor externa) até o centro (cor interna).
-func drawPlanetGradient(screen *ebiten.Image, cx, cy, radius float64, innerColor, outerColor color.RGBA) {
+func drawPlanetGradient(b *batch, cx, cy, radius float64, innerColor, outerColor color.RGBA) {
 	steps := 20
 	for i := 0; i < steps; i++ {
 		t := float64(i) / float64(steps-1)
 		r := radius * (1 - t)
 		clr := lerpColor(outerColor, innerColor, t)
-		drawFilledCircle(screen, cx, cy, r, clr)
+		drawFilledCircle(b, cx, cy, r, clr)
 	}
 }
 
 // drawSaturnRings desenha os anéis de Saturno com inclinação.
-func drawSaturnRings(screen *ebiten.Image, cx, cy, planetRadius float64) {
+func drawSaturnRings(b *batch, cx, cy, planetRadius float64) {
 	segments := 60
 	tilt := 20 * math.Pi / 180.0 // 20 graus de inclinação
 	outerX := planetRadius * 2.0
@@ -165,57 +258,457 @@ func drawSaturnRings(screen *ebiten.Image, cx, cy, planetRadius float64) {
 	innerY := planetRadius * 0.8
 
 	ringColor := color.RGBA{210, 180, 140, 180}
-	outerPoints := make([][2]float64, segments)
-	innerPoints := make([][2]float64, segments)
+	point := func(rx, ry, theta float64) (float64, float64) {
+		x, y := rx*math.Cos(theta), ry*math.Sin(theta)
+		return cx + x*math.Cos(tilt) - y*math.Sin(tilt), cy + x*math.Sin(tilt) + y*math.Cos(tilt)
+	}
+	add := func(x, y float64) {
+		x, y = b.point(x, y)
+		b.vertex(x, y, ringColor)
+	}
 	for i := 0; i < segments; i++ {
 		theta := 2 * math.Pi * float64(i) / float64(segments)
-		ox := outerX * math.Cos(theta)
-		oy := outerY * math.Sin(theta)
-		rx := ox*math.Cos(tilt) - oy*math.Sin(tilt)
-		ry := ox*math.Sin(tilt) + oy*math.Cos(tilt)
-		outerPoints[i] = [2]float64{cx + rx, cy + ry}
+		next := 2 * math.Pi * float64(i+1) / float64(segments)
+		base := b.reserve(4)
+		add(point(outerX, outerY, theta))
+		add(point(outerX, outerY, next))
+		add(point(innerX, innerY, next))
+		add(point(innerX, innerY, theta))
+		b.indices = append(b.indices, base, base+1, base+2, base, base+2, base+3)
+	}
+}
+
+// -------------------------
+// Câmera 2D
+// -------------------------
+
+// camera2D leva as coordenadas do mundo (em que o Sol fica no centro da
+// janela) para a tela: o ponto (x, y) do mundo aparece no centro e as
+// distâncias são multiplicadas por zoom. Com follow, o centro acompanha o
+// corpo selecionado.
+type camera2D struct {
+	x, y   float64
+	zoom   float64
+	w, h   float64 // tamanho da tela
+	follow bool
+}
 
-		ix := innerX * math.Cos(theta)
-		iy := innerY * math.Sin(theta)
-		rx2 := ix*math.Cos(tilt) - iy*math.Sin(tilt)
-		ry2 := ix*math.Sin(tilt) + iy*math.Cos(tilt)
-		innerPoints[i] = [2]float64{cx + rx2, cy + ry2}
+// Limites e passo do zoom pela roda do mouse.
+const (
+	minZoom  = 0.1
+	maxZoom  = 50
+	zoomStep = 1.15
+)
+
+// toScreen leva um ponto do mundo para a tela.
+func (c *camera2D) toScreen(x, y float64) (float64, float64) {
+	return (x-c.x)*c.zoom + c.w/2, (y-c.y)*c.zoom + c.h/2
+}
+
+// toWorld leva um ponto da tela (o cursor, por exemplo) para o mundo.
+func (c *camera2D) toWorld(x, y float64) (float64, float64) {
+	return (x-c.w/2)/c.zoom + c.x, (y-c.h/2)/c.zoom + c.y
+}
+
+// zoomAt multiplica o zoom por factor mantendo fixo o ponto do mundo sob o
+// ponto (sx, sy) da tela.
+func (c *camera2D) zoomAt(sx, sy, factor float64) {
+	wx, wy := c.toWorld(sx, sy)
+	c.zoom = math.Max(minZoom, math.Min(maxZoom, c.zoom*factor))
+	nx, ny := c.toWorld(sx, sy)
+	c.x += wx - nx
+	c.y += wy - ny
+}
+
+// pan arrasta a vista por (dx, dy) pixels da tela e desliga o follow.
+func (c *camera2D) pan(dx, dy float64) {
+	c.x -= dx / c.zoom
+	c.y -= dy / c.zoom
+	c.follow = false
+}
+
+// reset centra a vista no ponto dado, sem zoom.
+func (c *camera2D) reset(x, y float64) {
+	c.x, c.y, c.zoom, c.follow = x, y, 1, false
+}
+
+// bodyRef aponta para um corpo do mundo: um planeta, uma das suas luas ou
+// um asteroide. O valor zero não aponta para nada.
+type bodyRef struct {
+	planet   *Planet
+	moon     *Moon
+	asteroid *Asteroid
+}
+
+// valid informa se a referência aponta para algum corpo.
+func (r bodyRef) valid() bool {
+	return r.planet != nil || r.asteroid != nil
+}
+
+// primary devolve o centro em torno do qual o corpo orbita (o Sol ou, para
+// uma lua, o seu planeta) e o parâmetro gravitacional dele.
+func (sim *Simulation) primary(r bodyRef) (x, y, gm float64) {
+	if r.moon != nil {
+		return r.planet.X, r.planet.Y, planetScreenGM(r.planet)
 	}
+	return sim.sunX, sim.sunY, screenSunMu
+}
 
-	vertices := []ebiten.Vertex{}
-	indices := []uint16{}
-	for i := 0; i < segments; i++ {
-		next := (i + 1) % segments
-		base := uint16(len(vertices))
-		vertices = append(vertices,
-			ebiten.Vertex{
-				DstX: float32(outerPoints[i][0]), DstY: float32(outerPoints[i][1]),
-				SrcX: 0, SrcY: 0,
-				ColorR: float32(ringColor.R) / 255, ColorG: float32(ringColor.G) / 255,
-				ColorB: float32(ringColor.B) / 255, ColorA: float32(ringColor.A) / 255,
-			},
-			ebiten.Vertex{
-				DstX: float32(outerPoints[next][0]), DstY: float32(outerPoints[next][1]),
-				SrcX: 0, SrcY: 0,
-				ColorR: float32(ringColor.R) / 255, ColorG: float32(ringColor.G) / 255,
-				ColorB: float32(ringColor.B) / 255, ColorA: float32(ringColor.A) / 255,
-			},
-			ebiten.Vertex{
-				DstX: float32(innerPoints[next][0]), DstY: float32(innerPoints[next][1]),
-				SrcX: 0, SrcY: 0,
-				ColorR: float32(ringColor.R) / 255, ColorG: float32(ringColor.G) / 255,
-				ColorB: float32(ringColor.B) / 255, ColorA: float32(ringColor.A) / 255,
-			},
-			ebiten.Vertex{
-				DstX: float32(innerPoints[i][0]), DstY: float32(innerPoints[i][1]),
-				SrcX: 0, SrcY: 0,
-				ColorR: float32(ringColor.R) / 255, ColorG: float32(ringColor.G) / 255,
-				ColorB: float32(ringColor.B) / 255, ColorA: float32(ringColor.A) / 255,
-			},
-		)
-		indices = append(indices, base, base+1, base+2, base, base+2, base+3)
-	}
-	screen.DrawTriangles(vertices, indices, dummyImage, nil)
+// orbit devolve o raio, o ângulo e o lançamento do corpo, que definem a sua
+// posição em torno do primário.
+func (r bodyRef) orbit() (radius, angle *float64, f *fling) {
+	switch {
+	case r.moon != nil:
+		return &r.moon.OrbitRadius, &r.moon.Angle, &r.moon.Fling
+	case r.asteroid != nil:
+		return &r.asteroid.OrbitRadius, &r.asteroid.Angle, &r.asteroid.Fling
+	}
+	return &r.planet.OrbitRadius, &r.planet.Angle, &r.planet.Fling
+}
+
+// position devolve a posição do corpo no mundo.
+func (sim *Simulation) position(r bodyRef) (float64, float64) {
+	cx, cy, _ := sim.primary(r)
+	radius, angle, _ := r.orbit()
+	return cx + *radius*math.Cos(*angle), cy + *radius*math.Sin(*angle)
+}
+
+// pickRadius é o raio mínimo, em pixels da tela, para acertar um corpo com
+// o mouse, para que luas pequenas continuem clicáveis sem zoom.
+const pickRadius = 4
+
+// bodyAt devolve o corpo sob o ponto (x, y) do mundo, preferindo as luas e
+// deixando os asteroides por último.
+func (sim *Simulation) bodyAt(x, y float64) (bodyRef, bool) {
+	minR := pickRadius / sim.camera.zoom
+	for _, p := range sim.planets {
+		for _, m := range p.Moons {
+			mx, my := moonPosition(p, m)
+			if math.Hypot(x-mx, y-my) <= math.Max(m.Radius, minR) {
+				return bodyRef{planet: p, moon: m}, true
+			}
+		}
+	}
+	for _, p := range sim.planets {
+		if math.Hypot(x-p.X, y-p.Y) <= math.Max(float64(p.Radius), minR) {
+			return bodyRef{planet: p}, true
+		}
+	}
+	for i := range sim.asteroids {
+		a := &sim.asteroids[i]
+		ref := bodyRef{asteroid: a}
+		if ax, ay := sim.position(ref); math.Hypot(x-ax, y-ay) <= math.Max(a.Radius, minR) {
+			return ref, true
+		}
+	}
+	return bodyRef{}, false
+}
+
+// updateCamera trata a roda (zoom em torno do cursor), o arraste com o botão
+// do meio ou com o esquerdo fora de um corpo (pan), F (seguir o corpo
+// selecionado) e Home (volta ao Sol, sem zoom).
+func (sim *Simulation) updateCamera(w, h int) {
+	c := &sim.camera
+	c.w, c.h = float64(w), float64(h)
+	if c.zoom == 0 {
+		c.reset(sim.sunX, sim.sunY)
+	}
+	cx, cy := ebiten.CursorPosition()
+	if _, wy := ebiten.Wheel(); wy != 0 {
+		c.zoomAt(float64(cx), float64(cy), math.Pow(zoomStep, wy))
+	}
+	if sim.panning {
+		c.pan(float64(cx-sim.panX), float64(cy-sim.panY))
+	}
+	sim.panX, sim.panY = cx, cy
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonMiddle) {
+		sim.panning = true
+	}
+	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonMiddle) || inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+		sim.panning = false
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF) && sim.selected.valid() {
+		c.follow = !c.follow
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyHome) {
+		c.reset(sim.sunX, sim.sunY)
+	}
+}
+
+// followSelected centra a câmera no corpo selecionado, depois que ele se
+// moveu neste frame.
+func (sim *Simulation) followSelected() {
+	if sim.camera.follow && sim.selected.valid() {
+		sim.camera.x, sim.camera.y = sim.position(sim.selected)
+	}
+}
+
+// -------------------------
+// Arraste e lançamento dos corpos
+// -------------------------
+
+// fling guarda o estado de um corpo lançado no modo gravidade. Com active, o
+// corpo deixa o movimento circular e segue, pela gravidade do primário, a
+// órbita dada pela velocidade (vx, vy) em px/frame relativa a ele.
+type fling struct {
+	active bool
+	vx, vy float64
+}
+
+// flingSubsteps é o número de passos do integrador por frame.
+const flingSubsteps = 4
+
+// advance avança um frame o corpo na posição polar (r, angle) em torno de um
+// primário de parâmetro gm: pelo movimento circular a speed rad/frame ou,
+// se lançado, integrando a gravidade. Um corpo que cai no primário volta ao
+// movimento circular.
+func (f *fling) advance(r, angle, speed, gm float64) (float64, float64) {
+	if !f.active {
+		return r, angle + speed
+	}
+	pos := astro.Vec3{X: r * math.Cos(angle), Y: r * math.Sin(angle)}
+	vel := astro.Vec3{X: f.vx, Y: f.vy}
+	accel := func(_ float64, p astro.Vec3) astro.Vec3 { return astro.PointMassAccel(p, astro.Vec3{}, gm) }
+	dt := 1.0 / flingSubsteps
+	for i := 0; i < flingSubsteps; i++ {
+		pos, vel = astro.LeapfrogStep(0, pos, vel, dt, accel)
+	}
+	f.vx, f.vy = vel.X, vel.Y
+	if pos.Norm() < 1 {
+		f.active = false
+	}
+	return pos.Norm(), math.Atan2(pos.Y, pos.X)
+}
+
+// startDrag prende o corpo ao mouse, guardando a distância entre eles.
+func (sim *Simulation) startDrag(ref bodyRef, mouseX, mouseY float64) {
+	x, y := sim.position(ref)
+	sim.dragged = ref
+	sim.dragOffsetX, sim.dragOffsetY = x-mouseX, y-mouseY
+	sim.dragVX, sim.dragVY = 0, 0
+	_, _, f := ref.orbit()
+	f.active = false
+}
+
+// dragTo move o corpo arrastado para junto do mouse, atualizando o raio e o
+// ângulo em torno do primário e a velocidade do arraste (média móvel do
+// deslocamento por frame, relativo ao primário).
+func (sim *Simulation) dragTo(mouseX, mouseY float64) {
+	ref := sim.dragged
+	cx, cy, _ := sim.primary(ref)
+	radius, angle, _ := ref.orbit()
+	oldX, oldY := *radius*math.Cos(*angle), *radius*math.Sin(*angle)
+	dx, dy := mouseX+sim.dragOffsetX-cx, mouseY+sim.dragOffsetY-cy
+	*radius, *angle = math.Hypot(dx, dy), math.Atan2(dy, dx)
+	sim.dragVX = 0.5*sim.dragVX + 0.5*(dx-oldX)
+	sim.dragVY = 0.5*sim.dragVY + 0.5*(dy-oldY)
+}
+
+// endDrag solta o corpo: no modo gravidade ele é lançado com a velocidade do
+// arraste; senão volta ao movimento circular no raio em que foi solto.
+func (sim *Simulation) endDrag() {
+	if !sim.dragged.valid() {
+		return
+	}
+	if sim.gravityMode {
+		_, _, f := sim.dragged.orbit()
+		*f = fling{active: true, vx: sim.dragVX, vy: sim.dragVY}
+	}
+	sim.dragged = bodyRef{}
+}
+
+// isDragged informa se o corpo é o que está preso ao mouse.
+func (sim *Simulation) isDragged(ref bodyRef) bool {
+	return sim.dragged.valid() && sim.dragged == ref
+}
+
+// drawDrag mostra, no modo gravidade, a velocidade que o corpo arrastado
+// terá ao ser solto.
+func (sim *Simulation) drawDrag(b *batch) {
+	if !sim.gravityMode || !sim.dragged.valid() {
+		return
+	}
+	x, y := sim.position(sim.dragged)
+	const arrowFrames = 20 // comprimento da seta: deslocamento em 20 frames
+	drawThickLine(b, x, y, x+sim.dragVX*arrowFrames, y+sim.dragVY*arrowFrames, 2, color.RGBA{255, 220, 80, 220})
+}
+
+// -------------------------
+// Rastros das órbitas
+// -------------------------
+
+// trailBodies devolve os corpos que deixam rastro: os planetas, as luas e o
+// asteroide selecionado (o cinturão inteiro encheria a tela).
+func (sim *Simulation) trailBodies() []bodyRef {
+	var refs []bodyRef
+	for _, p := range sim.planets {
+		refs = append(refs, bodyRef{planet: p})
+		for _, m := range p.Moons {
+			refs = append(refs, bodyRef{planet: p, moon: m})
+		}
+	}
+	if sim.selected.asteroid != nil {
+		refs = append(refs, sim.selected)
+	}
+	return refs
+}
+
+// updateTrails registra a posição atual de cada corpo no seu rastro. Os
+// rastros só são mantidos enquanto estão visíveis (tecla T).
+func (sim *Simulation) updateTrails() {
+	if !sim.showTrails {
+		sim.trails = nil
+		return
+	}
+	if sim.trails == nil {
+		sim.trails = make(map[bodyRef]*trail.Trail[[2]float64])
+	}
+	seen := make(map[bodyRef]bool)
+	for _, ref := range sim.trailBodies() {
+		seen[ref] = true
+		t := sim.trails[ref]
+		if t == nil {
+			t = trail.New[[2]float64](sim.trailSeconds)
+			sim.trails[ref] = t
+		}
+		x, y := sim.position(ref)
+		t.Add([2]float64{x, y}, sim.time)
+	}
+	// Um asteroide que deixou de estar selecionado perde o rastro
+	for ref := range sim.trails {
+		if !seen[ref] {
+			delete(sim.trails, ref)
+		}
+	}
+}
+
+// drawTrails desenha o rastro de cada corpo na cor dele, cada vez mais
+// transparente para o passado.
+func (sim *Simulation) drawTrails(b *batch) {
+	for ref, t := range sim.trails {
+		clr := color.RGBA{169, 169, 169, 200}
+		switch {
+		case ref.moon != nil:
+			clr = ref.moon.OuterColor
+		case ref.planet != nil:
+			clr = ref.planet.OuterColor
+		}
+		t.Segments(sim.time, func(p1, p2 [2]float64, age float64) {
+			c := clr
+			c.A = uint8(180 * (1 - age))
+			drawThickLine(b, p1[0], p1[1], p2[0], p2[1], 1.5, c)
+		})
+	}
+}
+
+// -------------------------
+// Gravação de vídeo
+// -------------------------
+
+// stepsPerSecond é o número de passos da simulação por segundo simulado.
+const stepsPerSecond = 60
+
+// toggleRecording começa uma gravação ou termina a atual. Durante a
+// gravação, cada Update é seguido de um Draw (TPS sincronizado com o FPS),
+// de modo que o vídeo segue o relógio da simulação mesmo que a gravação
+// deixe os frames lentos.
+func (sim *Simulation) toggleRecording() {
+	if rec := sim.recorder; rec != nil {
+		sim.recorder, sim.capturePending = nil, false
+		ebiten.SetTPS(ebiten.DefaultTPS)
+		if err := rec.Close(); err != nil {
+			log.Print(err)
+			return
+		}
+		log.Printf("gravação salva em %s (%d quadros)", rec.Path, rec.Frames())
+		return
+	}
+	rec, err := recorder.Start(recorder.DefaultName(sim.recordFormat, time.Now()), sim.recordFormat, sim.recordFPS, stepsPerSecond)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	sim.recorder = rec
+	ebiten.SetTPS(ebiten.SyncWithFPS)
+}
+
+// tickRecorder conta um passo da simulação na gravação e marca a captura do
+// frame seguinte quando for a vez deste passo. Deve ser chamada uma vez por
+// Update, no fim do passo.
+func (sim *Simulation) tickRecorder() {
+	if rec := sim.recorder; rec != nil && rec.Tick() {
+		sim.capturePending = true
+	}
+}
+
+// captureFrame grava a tela, se houver captura pendente, e depois marca a
+// gravação na tela (a marca não entra no vídeo). Deve ser chamada no fim do
+// Draw, depois de toda a geometria ser enviada.
+func (sim *Simulation) captureFrame(screen *ebiten.Image) {
+	rec := sim.recorder
+	if rec == nil {
+		return
+	}
+	if sim.capturePending {
+		sim.capturePending = false
+		size := screen.Bounds().Size()
+		if len(sim.recordPixels) != 4*size.X*size.Y {
+			sim.recordPixels = make([]byte, 4*size.X*size.Y)
+		}
+		screen.ReadPixels(sim.recordPixels)
+		img := &image.RGBA{Pix: sim.recordPixels, Stride: 4 * size.X, Rect: image.Rectangle{Max: size}}
+		if err := rec.Add(img); err != nil {
+			log.Print(err)
+			sim.toggleRecording()
+			return
+		}
+	}
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("GRAVANDO %s (%d quadros) | F9: parar", rec.Path, rec.Frames()), 10, screen.Bounds().Dy()-40)
+}
+
+// -------------------------
+// Pôsteres
+// -------------------------
+
+// renderPoster desenha a cena atual, sem textos, numa imagem width x height.
+// A vista é a da janela ampliada para caber na imagem.
+func (sim *Simulation) renderPoster(width, height int) *image.RGBA {
+	saved := sim.camera
+	scale := math.Min(float64(width)/saved.w, float64(height)/saved.h)
+	sky := camera2D{x: saved.w / 2, y: saved.h / 2, zoom: scale, w: float64(width), h: float64(height)}
+	sim.camera.zoom *= scale
+	sim.camera.w, sim.camera.h = float64(width), float64(height)
+	target := ebiten.NewImage(width, height)
+	sim.drawScene(target, &sky, false)
+	sim.camera = saved
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	target.ReadPixels(img.Pix)
+	target.Deallocate()
+	return img
+}
+
+// savePoster salva um pôster em name (vazio: o nome padrão com a hora) com a
+// resolução de -resolucao (zero: a da janela) e os metadados da cena.
+func (sim *Simulation) savePoster(name string) {
+	if name == "" {
+		name = recorder.PosterName(time.Now())
+	}
+	width, height := sim.posterWidth, sim.posterHeight
+	if width == 0 {
+		width, height = int(sim.camera.w), int(sim.camera.h)
+	}
+	meta := []recorder.Metadata{
+		{Key: "Software", Value: "Simulação Avançada do Sistema Solar"},
+		{Key: "Simulation Time", Value: fmt.Sprintf("%.3f s", sim.time)},
+		{Key: "Camera", Value: fmt.Sprintf("centro (%.2f, %.2f), zoom %.3f", sim.camera.x, sim.camera.y, sim.camera.zoom)},
+		{Key: "Seed", Value: fmt.Sprint(sim.seed)},
+	}
+	if err := recorder.WritePNGMetadata(name, sim.renderPoster(width, height), meta); err != nil {
+		log.Print(err)
+		return
+	}
+	log.Printf("pôster salvo em %s (%dx%d)", name, width, height)
 }
 
 // -------------------------
@@ -227,6 +720,15 @@ type Star struct {
 	X, Y           float64
 	Phase, Speed   float64
 	BaseBrightness uint8
+	Radius         float64
+	Color          color.RGBA
+}
+
+// ConstellationFigure é uma constelação projetada no mapa do céu da tela.
+type ConstellationFigure struct {
+	Name           string
+	Segments       [][4]float64 // x1, y1, x2, y2
+	LabelX, LabelY float64
 }
 
 // Moon representa uma lua orbitando um planeta.
@@ -237,11 +739,14 @@ type Moon struct {
 	Radius      float64
 	InnerColor  color.RGBA
 	OuterColor  color.RGBA
+	Mass        float64 // massas terrestres
+	ShowSpheres bool    // esferas de influência e de Hill
+	Fling       fling
 }
 
-// Update atualiza a posição da lua.
-func (m *Moon) Update() {
-	m.Angle += m.OrbitSpeed
+// Update atualiza a posição da lua em torno de um planeta de parâmetro gm.
+func (m *Moon) Update(gm float64) {
+	m.OrbitRadius, m.Angle = m.Fling.advance(m.OrbitRadius, m.Angle, m.OrbitSpeed, gm)
 }
 
 // Asteroid representa uma partícula do cinturão de asteroides.
@@ -250,35 +755,31 @@ type Asteroid struct {
 	Angle       float64
 	OrbitSpeed  float64
 	Radius      float64
+	Fling       fling
 }
 
 // Update atualiza a posição do asteroide.
 func (a *Asteroid) Update() {
-	a.Angle += a.OrbitSpeed
+	a.OrbitRadius, a.Angle = a.Fling.advance(a.OrbitRadius, a.Angle, a.OrbitSpeed, screenSunMu)
 }
 
+// cometTailSeconds é a duração da cauda do cometa (20 frames).
+const cometTailSeconds = 20 * 0.016
+
 // Comet representa um cometa com cauda dinâmica.
 type Comet struct {
-	X, Y          float64
-	Angle         float64
-	Speed         float64
-	TailPoints    [][2]float64
-	TailMaxLength int
+	X, Y  float64
+	Angle float64
+	Speed float64
+	Tail  *trail.Trail[[2]float64]
 }
 
-// Update atualiza a posição do cometa e gerencia a cauda.
-func (c *Comet) Update() {
-	// Atualiza posição
+// Update atualiza a posição do cometa e registra na cauda a posição no
+// instante now.
+func (c *Comet) Update(now float64) {
 	c.X += c.Speed * math.Cos(c.Angle)
 	c.Y += c.Speed * math.Sin(c.Angle)
-
-	// Insere a posição atual na cauda (no início)
-	c.TailPoints = append([][2]float64{{c.X, c.Y}}, c.TailPoints...)
-
-	// Limita o comprimento da cauda
-	if len(c.TailPoints) > c.TailMaxLength {
-		c.TailPoints = c.TailPoints[:c.TailMaxLength]
-	}
+	c.Tail.Add([2]float64{c.X, c.Y}, now)
 }
 
 // -------------------------
@@ -293,20 +794,15 @@ type Planet struct {
 	X, Y        float64
 	InnerColor  color.RGBA
 	OuterColor  color.RGBA
-	Draggable   bool
-	IsDragged   bool
+	Mass        float64 // massas terrestres
+	ShowSpheres bool    // esferas de influência e de Hill
+	Fling       fling
 	Moons       []*Moon
 }
 
 // Update atualiza o ângulo da órbita do planeta.
 func (p *Planet) Update() {
-	if !p.IsDragged {
-		p.Angle += p.OrbitSpeed
-	}
-	// Atualiza as luas
-	for _, m := range p.Moons {
-		m.Update()
-	}
+	p.OrbitRadius, p.Angle = p.Fling.advance(p.OrbitRadius, p.Angle, p.OrbitSpeed, screenSunMu)
 }
 
 // -------------------------
@@ -316,20 +812,52 @@ type Simulation struct {
 	sunX, sunY               float64
 	sunRadius                float64
 	planets                  []*Planet
-	draggedPlanet            *Planet
+	dragged                  bodyRef // corpo preso ao mouse
 	dragOffsetX, dragOffsetY float64
+	dragVX, dragVY           float64 // velocidade do arraste, px/frame
+	gravityMode              bool    // ao soltar, o corpo é lançado (tecla G)
 	stars                    []Star
 	asteroids                []Asteroid
 	comet                    *Comet
 	time                     float64
+	constellations           []ConstellationFigure
+	showConstellations       bool
+	lagrangePair             int             // 0 desliga; i > 0 escolhe lagrangePairs[i-1]
+	showJacobi               bool            // curvas de velocidade zero do par escolhido
+	jacobiPair               int             // par para o qual jacobiLines foi calculado
+	jacobiLines              [][][4]float64  // um conjunto de segmentos por nível de C
+	insideSphere             map[string]bool // quem está dentro de cada esfera
+	notifications            []notification
+	batch                    batch         // geometria do frame, reaproveitada entre frames
+	lightImage               *ebiten.Image // luz do Sol com as sombras recortadas
+	camera                   camera2D
+	selected                 bodyRef            // último corpo clicado (seguido com F)
+	panning                  bool               // arrastando a vista
+	panX, panY               int                // cursor no frame anterior, para o pan
+	recorder                 *recorder.Recorder // gravação em andamento (tecla F9)
+	recordFormat             recorder.Format
+	recordFPS                float64
+	recordPixels             []byte // pixels lidos da tela para a gravação
+	capturePending           bool   // o último passo pediu um frame da gravação
+	trails                   map[bodyRef]*trail.Trail[[2]float64]
+	trailSeconds             float64    // duração dos rastros, em segundos simulados
+	showTrails               bool       // rastros das órbitas (tecla T)
+	seed                     int64      // semente dos asteroides e das estrelas
+	rng                      *rand.Rand // gerador criado com seed
+	posterWidth              int        // resolução dos pôsteres (tecla F10)
+	posterHeight             int
+	posterFile               string // -poster: salva um pôster no primeiro passo e sai
 }
 
-// NewSimulation inicializa a simulação com planetas, estrelas, cinturão de asteroides e cometa.
-func NewSimulation() *Simulation {
+// NewSimulation inicializa a simulação com planetas, estrelas, cinturão de
+// asteroides e cometa; seed fixa as partes aleatórias.
+func NewSimulation(seed int64) *Simulation {
 	sim := &Simulation{
 		sunRadius: 40,
 		planets:   make([]*Planet, 0),
 		time:      0,
+		seed:      seed,
+		rng:       rand.New(rand.NewSource(seed)),
 	}
 
 	// --- Planetas ---
@@ -342,7 +870,7 @@ func NewSimulation() *Simulation {
 		OrbitSpeed:  0.04,
 		InnerColor:  color.RGBA{169, 169, 169, 255},
 		OuterColor:  color.RGBA{105, 105, 105, 255},
-		Draggable:   false,
+		Mass:        0.0553,
 	})
 	// Vênus
 	sim.planets = append(sim.planets, &Planet{
@@ -353,7 +881,7 @@ func NewSimulation() *Simulation {
 		OrbitSpeed:  0.03,
 		InnerColor:  color.RGBA{255, 215, 0, 255},
 		OuterColor:  color.RGBA{218, 165, 32, 255},
-		Draggable:   false,
+		Mass:        0.815,
 	})
 	// Terra (arrastável) – com uma lua
 	terra := &Planet{
@@ -364,7 +892,7 @@ func NewSimulation() *Simulation {
 		OrbitSpeed:  0.02,
 		InnerColor:  color.RGBA{100, 149, 237, 255},
 		OuterColor:  color.RGBA{25, 25, 112, 255},
-		Draggable:   true,
+		Mass:        1,
 	}
 	terra.Moons = []*Moon{
 		{
@@ -374,6 +902,7 @@ func NewSimulation() *Simulation {
 			Radius:      3,
 			InnerColor:  color.RGBA{240, 240, 240, 255},
 			OuterColor:  color.RGBA{160, 160, 160, 255},
+			Mass:        0.0123,
 		},
 	}
 	sim.planets = append(sim.planets, terra)
@@ -386,7 +915,7 @@ func NewSimulation() *Simulation {
 		OrbitSpeed:  0.015,
 		InnerColor:  color.RGBA{205, 92, 92, 255},
 		OuterColor:  color.RGBA{139, 69, 19, 255},
-		Draggable:   false,
+		Mass:        0.107,
 	})
 	// Júpiter
 	sim.planets = append(sim.planets, &Planet{
@@ -397,7 +926,7 @@ func NewSimulation() *Simulation {
 		OrbitSpeed:  0.01,
 		InnerColor:  color.RGBA{222, 184, 135, 255},
 		OuterColor:  color.RGBA{160, 82, 45, 255},
-		Draggable:   false,
+		Mass:        317.8,
 	})
 	// Saturno (com anéis)
 	sim.planets = append(sim.planets, &Planet{
@@ -408,7 +937,7 @@ func NewSimulation() *Simulation {
 		OrbitSpeed:  0.008,
 		InnerColor:  color.RGBA{222, 203, 164, 255},
 		OuterColor:  color.RGBA{210, 180, 140, 255},
-		Draggable:   false,
+		Mass:        95.2,
 	})
 	// Urano
 	sim.planets = append(sim.planets, &Planet{
@@ -419,7 +948,7 @@ func NewSimulation() *Simulation {
 		OrbitSpeed:  0.006,
 		InnerColor:  color.RGBA{175, 238, 238, 255},
 		OuterColor:  color.RGBA{72, 209, 204, 255},
-		Draggable:   false,
+		Mass:        14.5,
 	})
 	// Netuno
 	sim.planets = append(sim.planets, &Planet{
@@ -430,7 +959,7 @@ func NewSimulation() *Simulation {
 		OrbitSpeed:  0.005,
 		InnerColor:  color.RGBA{65, 105, 225, 255},
 		OuterColor:  color.RGBA{25, 25, 112, 255},
-		Draggable:   false,
+		Mass:        17.1,
 	})
 	// Plutão
 	sim.planets = append(sim.planets, &Planet{
@@ -441,21 +970,22 @@ func NewSimulation() *Simulation {
 		OrbitSpeed:  0.004,
 		InnerColor:  color.RGBA{205, 133, 63, 255},
 		OuterColor:  color.RGBA{139, 69, 19, 255},
-		Draggable:   false,
+		Mass:        0.0022,
 	})
 
 	// --- Campo de Estrelas ---
+	// Usa o catálogo BSC quando disponível; senão, pontos aleatórios.
 	w, h := ebiten.WindowSize()
-	starCount := 200
-	sim.stars = make([]Star, starCount)
-	for i := 0; i < starCount; i++ {
-		sim.stars[i] = Star{
-			X:              float64(rand.Intn(w)),
-			Y:              float64(rand.Intn(h)),
-			Phase:          rand.Float64() * 2 * math.Pi,
-			Speed:          0.005 + rand.Float64()*0.005,
-			BaseBrightness: uint8(100 + rand.Intn(155)),
+	if cat, err := catalog.LoadBSC(starCatalogFile); err == nil {
+		sim.stars = catalogStars(sim.rng, cat, w, h)
+		if cons, err := catalog.LoadConstellations(constellationFile); err == nil {
+			sim.constellations = constellationFigures(cat, cons, w, h)
+		} else {
+			log.Printf("figuras das constelações indisponíveis: %v", err)
 		}
+	} else {
+		log.Printf("catálogo de estrelas indisponível (%v); usando estrelas aleatórias", err)
+		sim.stars = randomStars(sim.rng, 200, w, h)
 	}
 
 	// --- Cinturão de Asteroides ---
@@ -463,95 +993,620 @@ func NewSimulation() *Simulation {
 	sim.asteroids = make([]Asteroid, asteroidCount)
 	for i := 0; i < asteroidCount; i++ {
 		// Distribuídos entre 210 e 240 (entre Marte e Júpiter)
-		orbitRadius := 210 + rand.Float64()*30
+		orbitRadius := 210 + sim.rng.Float64()*30
 		sim.asteroids[i] = Asteroid{
 			OrbitRadius: orbitRadius,
-			Angle:       rand.Float64() * 2 * math.Pi,
-			OrbitSpeed:  0.008 + rand.Float64()*0.004,
-			Radius:      1 + rand.Float64()*1.5,
+			Angle:       sim.rng.Float64() * 2 * math.Pi,
+			OrbitSpeed:  0.008 + sim.rng.Float64()*0.004,
+			Radius:      1 + sim.rng.Float64()*1.5,
 		}
 	}
 
 	// --- Cometa ---
 	sim.comet = &Comet{
 		// Começa fora da tela, na parte superior esquerda
-		X:             -50,
-		Y:             -50,
-		Angle:         math.Pi / 4, // 45° em direção à direita/inferior
-		Speed:         4.0,
-		TailPoints:    make([][2]float64, 0),
-		TailMaxLength: 20,
+		X:     -50,
+		Y:     -50,
+		Angle: math.Pi / 4, // 45° em direção à direita/inferior
+		Speed: 4.0,
+		Tail:  trail.New[[2]float64](cometTailSeconds),
 	}
 
 	return sim
 }
 
+// catalogStars projeta as estrelas do catálogo na tela como um mapa do céu
+// (ascensão reta na horizontal, crescendo para a esquerda, e declinação na vertical).
+func catalogStars(rng *rand.Rand, cat []catalog.Star, w, h int) []Star {
+	stars := make([]Star, 0, len(cat))
+	for _, s := range cat {
+		x, y := skyToScreen(s.RA, s.Dec, w, h)
+		stars = append(stars, Star{
+			X:              x,
+			Y:              y,
+			Phase:          rng.Float64() * 2 * math.Pi,
+			Speed:          0.005 + rng.Float64()*0.005,
+			BaseBrightness: s.Brightness(),
+			Radius:         s.Size() * 0.6,
+			Color:          s.Color(),
+		})
+	}
+	return stars
+}
+
+// constellationFigures projeta as figuras das constelações no mapa do céu.
+// Traços que cruzam a borda do mapa (AR = 0h) são omitidos.
+func constellationFigures(cat []catalog.Star, cons []catalog.Constellation, w, h int) []ConstellationFigure {
+	index := catalog.IndexByHR(cat)
+	figures := make([]ConstellationFigure, 0, len(cons))
+	for _, c := range cons {
+		center, ok := c.Center(index)
+		if !ok {
+			continue
+		}
+		fig := ConstellationFigure{Name: c.Name}
+		ra, dec := astro.CartesianToSpherical(center)
+		fig.LabelX, fig.LabelY = skyToScreen(ra, dec, w, h)
+		for _, seg := range c.Segments(index) {
+			x1, y1 := skyToScreen(seg[0].RA, seg[0].Dec, w, h)
+			x2, y2 := skyToScreen(seg[1].RA, seg[1].Dec, w, h)
+			if math.Abs(x2-x1) > float64(w)/2 {
+				continue
+			}
+			fig.Segments = append(fig.Segments, [4]float64{x1, y1, x2, y2})
+		}
+		figures = append(figures, fig)
+	}
+	return figures
+}
+
+// skyToScreen converte (AR, Dec) em radianos para a projeção do mapa do céu.
+func skyToScreen(ra, dec float64, w, h int) (float64, float64) {
+	return (1 - ra/(2*math.Pi)) * float64(w), (0.5 - dec/math.Pi) * float64(h)
+}
+
+// randomStars espalha estrelas brancas em posições aleatórias da tela.
+func randomStars(rng *rand.Rand, count, w, h int) []Star {
+	stars := make([]Star, count)
+	for i := 0; i < count; i++ {
+		stars[i] = Star{
+			X:              float64(rng.Intn(w)),
+			Y:              float64(rng.Intn(h)),
+			Phase:          rng.Float64() * 2 * math.Pi,
+			Speed:          0.005 + rng.Float64()*0.005,
+			BaseBrightness: uint8(100 + rng.Intn(155)),
+			Radius:         1,
+			Color:          color.RGBA{255, 255, 255, 255},
+		}
+	}
+	return stars
+}
+
+// -------------------------
+// Pontos de Lagrange e curvas de Jacobi
+// -------------------------
+
+// lagrangePairs são os pares primário–secundário alternados com a tecla L.
+var lagrangePairs = [][2]astro.Body{
+	{astro.Sun, astro.Earth},
+	{astro.Earth, astro.Moon},
+	{astro.Sun, astro.Jupiter},
+}
+
+// jacobiColors são as cores das curvas de velocidade zero nos níveis de L1, L2 e L3.
+var jacobiColors = []color.RGBA{
+	{120, 255, 160, 90},
+	{120, 200, 255, 90},
+	{255, 200, 120, 90},
+}
+
+// bodyPosition devolve a posição no mundo de um corpo da simulação: o Sol, um
+// planeta ou a Lua (primeira lua da Terra).
+func (sim *Simulation) bodyPosition(b astro.Body) (float64, float64, bool) {
+	if b == astro.Sun {
+		return sim.sunX, sim.sunY, true
+	}
+	want := b
+	if b == astro.Moon {
+		want = astro.Earth
+	}
+	for _, p := range sim.planets {
+		if pb, ok := astro.BodyByName(p.Name); !ok || pb != want {
+			continue
+		}
+		if b != astro.Moon {
+			return p.X, p.Y, true
+		}
+		if len(p.Moons) == 0 {
+			return 0, 0, false
+		}
+		m := p.Moons[0]
+		return p.X + m.OrbitRadius*math.Cos(m.Angle), p.Y + m.OrbitRadius*math.Sin(m.Angle), true
+	}
+	return 0, 0, false
+}
+
+// synodicToWorld devolve a função que leva o referencial girante normalizado
+// do par escolhido (baricentro na origem, distância unitária) para o mundo.
+func (sim *Simulation) synodicToWorld() (func(x, y float64) (float64, float64), bool) {
+	if sim.lagrangePair == 0 {
+		return nil, false
+	}
+	pair := lagrangePairs[sim.lagrangePair-1]
+	x1, y1, ok1 := sim.bodyPosition(pair[0])
+	x2, y2, ok2 := sim.bodyPosition(pair[1])
+	if !ok1 || !ok2 {
+		return nil, false
+	}
+	mu := astro.MassRatio(pair[0], pair[1])
+	dx, dy := x2-x1, y2-y1
+	// Com o eixo y da tela para baixo, o sentido do movimento é (−dy, dx)
+	return func(x, y float64) (float64, float64) {
+		x += mu
+		return x1 + x*dx - y*dy, y1 + x*dy + y*dx
+	}, true
+}
+
+// jacobiContours traça por marching squares as curvas 2Ω(x, y) = c do
+// referencial girante normalizado, numa grade n×n de meia largura extent.
+func jacobiContours(mu, c float64, n int, extent float64) [][4]float64 {
+	step := 2 * extent / float64(n)
+	f := make([]float64, (n+1)*(n+1))
+	for j := 0; j <= n; j++ {
+		for i := 0; i <= n; i++ {
+			x, y := -extent+float64(i)*step, -extent+float64(j)*step
+			f[j*(n+1)+i] = 2*astro.EffectivePotential(mu, x, y) - c
+		}
+	}
+	var segs [][4]float64
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			x0, y0 := -extent+float64(i)*step, -extent+float64(j)*step
+			// Cantos no sentido anti-horário a partir de (x0, y0)
+			corners := [4][3]float64{
+				{x0, y0, f[j*(n+1)+i]},
+				{x0 + step, y0, f[j*(n+1)+i+1]},
+				{x0 + step, y0 + step, f[(j+1)*(n+1)+i+1]},
+				{x0, y0 + step, f[(j+1)*(n+1)+i]},
+			}
+			var pts [][2]float64
+			for k := 0; k < 4; k++ {
+				a, b := corners[k], corners[(k+1)%4]
+				if (a[2] < 0) == (b[2] < 0) {
+					continue
+				}
+				t := a[2] / (a[2] - b[2])
+				pts = append(pts, [2]float64{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])})
+			}
+			for k := 0; k+1 < len(pts); k += 2 {
+				segs = append(segs, [4]float64{pts[k][0], pts[k][1], pts[k+1][0], pts[k+1][1]})
+			}
+		}
+	}
+	return segs
+}
+
+// updateJacobi recalcula as curvas de velocidade zero quando o par muda. Os
+// níveis são as constantes de Jacobi de L1, L2 e L3, onde as regiões
+// permitidas se abrem umas para as outras.
+func (sim *Simulation) updateJacobi() {
+	if sim.jacobiPair == sim.lagrangePair {
+		return
+	}
+	sim.jacobiPair = sim.lagrangePair
+	sim.jacobiLines = nil
+	if sim.lagrangePair == 0 {
+		return
+	}
+	pair := lagrangePairs[sim.lagrangePair-1]
+	mu := astro.MassRatio(pair[0], pair[1])
+	points := astro.SynodicLagrangePoints(mu)
+	for _, l := range points[:3] {
+		c := astro.JacobiConstant(mu, l, astro.Vec3{})
+		sim.jacobiLines = append(sim.jacobiLines, jacobiContours(mu, c, 240, 1.6))
+	}
+}
+
+// drawLagrange desenha os pontos L1–L5 do par escolhido e, se ligadas, as
+// curvas de velocidade zero.
+func (sim *Simulation) drawLagrange(b *batch) {
+	toWorld, ok := sim.synodicToWorld()
+	if !ok {
+		return
+	}
+	if sim.showJacobi {
+		for level, segs := range sim.jacobiLines {
+			clr := jacobiColors[level%len(jacobiColors)]
+			for _, s := range segs {
+				x1, y1 := toWorld(s[0], s[1])
+				x2, y2 := toWorld(s[2], s[3])
+				drawThickLine(b, x1, y1, x2, y2, 1, clr)
+			}
+		}
+	}
+	pair := lagrangePairs[sim.lagrangePair-1]
+	markerColor := color.RGBA{120, 255, 160, 220}
+	for i, p := range astro.SynodicLagrangePoints(astro.MassRatio(pair[0], pair[1])) {
+		x, y := toWorld(p.X, p.Y)
+		drawCircleOutline(b, x, y, 4/sim.camera.zoom, 1, markerColor)
+		sx, sy := sim.camera.toScreen(x, y)
+		b.print(astro.LagrangeNames[i], int(sx)+6, int(sy)-6)
+	}
+	b.print("Lagrange: "+pair[0].String()+"-"+pair[1].String()+" (L: par | J: curvas de Jacobi)", 10, 10)
+}
+
+// -------------------------
+// Esferas de influência (Laplace) e de Hill
+// -------------------------
+
+// Parâmetros gravitacionais da tela (pixels³/frame²): o Sol, calibrado para
+// que a órbita da Terra (0,02 rad/frame a 160 px) seja kepleriana, e a Terra,
+// calibrada pela órbita da Lua (0,05 rad/frame a 20 px).
+const (
+	screenSunMu   = 0.02 * 0.02 * 160 * 160 * 160
+	earthScreenGM = 0.05 * 0.05 * 20 * 20 * 20
+)
+
+// sunEarthMasses é a massa do Sol em massas terrestres.
+const sunEarthMasses = 332946.0
+
+// planetScreenGM é o μ do planeta na tela, proporcional à massa real a partir
+// do μ da Terra.
+func planetScreenGM(p *Planet) float64 {
+	return earthScreenGM * p.Mass
+}
+
+// sphereDisplayScale aumenta só o desenho das esferas: com a razão de massas
+// real quase todas ficariam dentro do próprio corpo. Os avisos usam o raio real.
+const sphereDisplayScale = 10
+
+// influenceSphere é uma esfera (um círculo na tela) de influência ou de Hill
+// de um corpo, relativa ao seu primário.
+type influenceSphere struct {
+	owner   string
+	hill    bool
+	x, y, r float64
+	visible bool
+}
+
+func (s influenceSphere) name() string {
+	if s.hill {
+		return "esfera de Hill de " + s.owner
+	}
+	return "esfera de influência de " + s.owner
+}
+
+// moonName dá um nome à i-ésima lua do planeta.
+func moonName(p *Planet, i int) string {
+	return fmt.Sprintf("Lua %d de %s", i+1, p.Name)
+}
+
+// moonPosition devolve a posição da lua na tela.
+func moonPosition(p *Planet, m *Moon) (float64, float64) {
+	return p.X + m.OrbitRadius*math.Cos(m.Angle), p.Y + m.OrbitRadius*math.Sin(m.Angle)
+}
+
+// spheres lista as esferas dos planetas (relativas ao Sol) e das luas
+// (relativas ao planeta). Os raios dependem só da razão de massas real, que é
+// a mesma nas unidades da tela.
+func (sim *Simulation) spheres() []influenceSphere {
+	var out []influenceSphere
+	for _, p := range sim.planets {
+		out = append(out,
+			influenceSphere{p.Name, false, p.X, p.Y, astro.SphereOfInfluence(p.OrbitRadius, p.Mass, sunEarthMasses), p.ShowSpheres},
+			influenceSphere{p.Name, true, p.X, p.Y, astro.HillRadius(p.OrbitRadius, p.Mass, sunEarthMasses), p.ShowSpheres})
+		for i, m := range p.Moons {
+			mx, my := moonPosition(p, m)
+			out = append(out,
+				influenceSphere{moonName(p, i), false, mx, my, astro.SphereOfInfluence(m.OrbitRadius, m.Mass, p.Mass), m.ShowSpheres},
+				influenceSphere{moonName(p, i), true, mx, my, astro.HillRadius(m.OrbitRadius, m.Mass, p.Mass), m.ShowSpheres})
+		}
+	}
+	return out
+}
+
+// notification é um aviso na tela, mostrado por notificationSeconds.
+type notification struct {
+	text string
+	time float64
+}
+
+const notificationSeconds = 5
+
+// updateSpheres avisa quando uma lua ou o cometa entra ou sai de uma esfera.
+// Na primeira verificação só guarda o estado.
+func (sim *Simulation) updateSpheres() {
+	type tracer struct {
+		name string
+		x, y float64
+	}
+	tracers := []tracer{{"Cometa", sim.comet.X, sim.comet.Y}}
+	for _, p := range sim.planets {
+		for i, m := range p.Moons {
+			mx, my := moonPosition(p, m)
+			tracers = append(tracers, tracer{moonName(p, i), mx, my})
+		}
+	}
+	first := sim.insideSphere == nil
+	if first {
+		sim.insideSphere = make(map[string]bool)
+	}
+	for _, sph := range sim.spheres() {
+		for _, t := range tracers {
+			if t.name == sph.owner {
+				continue
+			}
+			key := t.name + "|" + sph.name()
+			inside := math.Hypot(t.x-sph.x, t.y-sph.y) < sph.r
+			if inside == sim.insideSphere[key] {
+				continue
+			}
+			sim.insideSphere[key] = inside
+			if first {
+				continue
+			}
+			verb := "saiu da"
+			if inside {
+				verb = "entrou na"
+			}
+			sim.notifications = append(sim.notifications, notification{t.name + " " + verb + " " + sph.name(), sim.time})
+		}
+	}
+	for len(sim.notifications) > 0 && sim.time-sim.notifications[0].time > notificationSeconds {
+		sim.notifications = sim.notifications[1:]
+	}
+}
+
+// toggleSpheresAt liga ou desliga as esferas do planeta ou da lua sob o mouse.
+func (sim *Simulation) toggleSpheresAt(x, y float64) {
+	for _, p := range sim.planets {
+		for _, m := range p.Moons {
+			mx, my := moonPosition(p, m)
+			if math.Hypot(x-mx, y-my) <= m.Radius+2 {
+				m.ShowSpheres = !m.ShowSpheres
+				return
+			}
+		}
+		if math.Hypot(x-p.X, y-p.Y) <= p.Radius {
+			p.ShowSpheres = !p.ShowSpheres
+			return
+		}
+	}
+}
+
+// drawSpheres desenha as esferas ligadas como círculos translúcidos: a de
+// influência em azul e a de Hill em laranja.
+func (sim *Simulation) drawSpheres(b *batch) {
+	shown := false
+	for _, sph := range sim.spheres() {
+		if !sph.visible {
+			continue
+		}
+		fill, line := color.RGBA{80, 140, 255, 25}, color.RGBA{80, 140, 255, 110}
+		if sph.hill {
+			fill, line = color.RGBA{255, 160, 60, 25}, color.RGBA{255, 160, 60, 110}
+		}
+		r := sph.r * sphereDisplayScale
+		drawFilledCircle(b, sph.x, sph.y, r, fill)
+		drawCircleOutline(b, sph.x, sph.y, r, 1, line)
+		shown = true
+	}
+	y := 40
+	if shown {
+		b.print(fmt.Sprintf("Esferas desenhadas ×%d (só na tela; os avisos usam o raio real)", sphereDisplayScale), 10, y)
+		y += 16
+	}
+	for i, n := range sim.notifications {
+		b.print(n.text, 10, y+i*16)
+	}
+}
+
+// -------------------------
+// Luz do Sol e sombras
+// -------------------------
+
+// Cores do brilho radial do Sol: no centro e na borda do alcance da luz.
+var (
+	sunlightCore = color.RGBA{255, 255, 180, 150}
+	sunlightEdge = color.RGBA{255, 255, 200, 0}
+)
+
+// sunlightSegments é o número de fatias do leque de luz.
+const sunlightSegments = 90
+
+// occluder é um corpo que bloqueia a luz do Sol.
+type occluder struct {
+	x, y, r float64
+}
+
+// occluders lista todos os corpos que fazem sombra: planetas, luas,
+// asteroides e o núcleo do cometa.
+func (sim *Simulation) occluders() []occluder {
+	var out []occluder
+	for _, p := range sim.planets {
+		out = append(out, occluder{p.X, p.Y, float64(p.Radius)})
+		for _, m := range p.Moons {
+			mx, my := moonPosition(p, m)
+			out = append(out, occluder{mx, my, m.Radius})
+		}
+	}
+	for _, a := range sim.asteroids {
+		out = append(out, occluder{sim.sunX + a.OrbitRadius*math.Cos(a.Angle), sim.sunY + a.OrbitRadius*math.Sin(a.Angle), a.Radius})
+	}
+	return append(out, occluder{sim.comet.X, sim.comet.Y, 4})
+}
+
+// drawSunlight desenha a luz do Sol numa imagem própria: um leque com
+// gradiente radial do qual são recortados, com BlendDestinationOut, o disco
+// de cada corpo e a cunha de sombra atrás dele. A cunha é limitada pelas
+// tangentes que partem do centro do Sol, calculadas analiticamente, então o
+// custo é proporcional ao número de corpos. A imagem é então composta sobre
+// a tela.
+func (sim *Simulation) drawSunlight(b *batch, screen *ebiten.Image) {
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	if sim.lightImage == nil || sim.lightImage.Bounds().Dx() != w || sim.lightImage.Bounds().Dy() != h {
+		sim.lightImage = ebiten.NewImage(w, h)
+	}
+	b.use(sim.lightImage, ebiten.Blend{})
+	sim.lightImage.Clear()
+	// Alcance da luz no mundo: o bastante para cobrir a tela inteira
+	reach := math.Hypot(float64(w), float64(h))/sim.camera.zoom + math.Hypot(sim.camera.x-sim.sunX, sim.camera.y-sim.sunY)
+	vertex := func(x, y float64, clr color.RGBA) {
+		x, y = b.point(x, y)
+		b.vertex(x, y, clr)
+	}
+
+	// Leque de luz: a cor dos vértices faz o gradiente do centro à borda
+	base := b.reserve(sunlightSegments + 2)
+	vertex(sim.sunX, sim.sunY, sunlightCore)
+	for i := 0; i <= sunlightSegments; i++ {
+		theta := 2 * math.Pi * float64(i) / sunlightSegments
+		vertex(sim.sunX+reach*math.Cos(theta), sim.sunY+reach*math.Sin(theta), sunlightEdge)
+	}
+	for i := 0; i < sunlightSegments; i++ {
+		b.indices = append(b.indices, base, base+uint16(i+1), base+uint16(i+2))
+	}
+
+	// Sombras: o disco do corpo e a cunha entre as tangentes, até o alcance
+	b.use(sim.lightImage, ebiten.BlendDestinationOut)
+	opaque := color.RGBA{0, 0, 0, 255}
+	for _, o := range sim.occluders() {
+		dx, dy := o.x-sim.sunX, o.y-sim.sunY
+		d := math.Hypot(dx, dy)
+		if d <= o.r {
+			continue
+		}
+		drawFilledCircle(b, o.x, o.y, o.r, opaque)
+		theta := math.Atan2(dy, dx)
+		half := math.Asin(o.r / d)
+		near := d * math.Cos(half) // distância do Sol aos pontos de tangência
+		base := b.reserve(4)
+		for _, side := range []float64{-half, half} {
+			vertex(sim.sunX+near*math.Cos(theta+side), sim.sunY+near*math.Sin(theta+side), opaque)
+		}
+		for _, side := range []float64{half, -half} {
+			vertex(sim.sunX+reach*math.Cos(theta+side), sim.sunY+reach*math.Sin(theta+side), opaque)
+		}
+		b.indices = append(b.indices, base, base+1, base+2, base, base+2, base+3)
+	}
+
+	b.use(screen, ebiten.Blend{})
+	screen.DrawImage(sim.lightImage, nil)
+}
+
 // Update é chamado a cada frame.
 func (sim *Simulation) Update() error {
 	w, h := ebiten.WindowSize()
 	sim.sunX = float64(w) / 2
 	sim.sunY = float64(h) / 2
 	sim.time += 0.016 // Aproximadamente 60 fps
+	sim.updateCamera(w, h)
 
 	// Atualiza as estrelas (twinkling)
 	for i := range sim.stars {
 		sim.stars[i].Phase += sim.stars[i].Speed
 	}
 
+	// Alterna as figuras das constelações
+	if inpututil.IsKeyJustPressed(ebiten.KeyF9) {
+		sim.toggleRecording()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyC) {
+		sim.showConstellations = !sim.showConstellations
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyT) {
+		sim.showTrails = !sim.showTrails
+	}
+
+	// Alterna o par dos pontos de Lagrange e as curvas de Jacobi
+	if inpututil.IsKeyJustPressed(ebiten.KeyL) {
+		sim.lagrangePair = (sim.lagrangePair + 1) % (len(lagrangePairs) + 1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyJ) {
+		sim.showJacobi = !sim.showJacobi
+	}
+	sim.updateJacobi()
+
+	// Esferas de influência e de Hill: I liga todas, o botão direito liga as
+	// do corpo sob o mouse
+	if inpututil.IsKeyJustPressed(ebiten.KeyI) {
+		for _, p := range sim.planets {
+			p.ShowSpheres = !p.ShowSpheres
+		}
+	}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		cx, cy := ebiten.CursorPosition()
+		sim.toggleSpheresAt(sim.camera.toWorld(float64(cx), float64(cy)))
+	}
+
 	// Atualiza os asteroides
 	for i := range sim.asteroids {
-		sim.asteroids[i].Update()
+		if !sim.isDragged(bodyRef{asteroid: &sim.asteroids[i]}) {
+			sim.asteroids[i].Update()
+		}
 	}
 
 	// Atualiza o cometa
-	sim.comet.Update()
+	sim.comet.Update(sim.time)
 	// Se o cometa sair da tela, reinicia sua posição
 	if sim.comet.X > float64(w)+50 || sim.comet.Y > float64(h)+50 {
 		sim.comet.X = -50
 		sim.comet.Y = -50
-		sim.comet.TailPoints = sim.comet.TailPoints[:0]
+		sim.comet.Tail.Reset()
 	}
 
-	// Processa entrada do mouse para planetas arrastáveis
+	// Processa entrada do mouse: um clique seleciona o corpo (e arrasta os
+	// planetas arrastáveis); fora de qualquer corpo, arrasta a vista
 	mx, my := ebiten.CursorPosition()
-	mouseX := float64(mx)
-	mouseY := float64(my)
+	mouseX, mouseY := sim.camera.toWorld(float64(mx), float64(my))
 	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		for _, p := range sim.planets {
-			if p.Draggable {
-				dx := mouseX - p.X
-				dy := mouseY - p.Y
-				if math.Hypot(dx, dy) <= p.Radius {
-					sim.draggedPlanet = p
-					p.IsDragged = true
-					sim.dragOffsetX = p.X - mouseX
-					sim.dragOffsetY = p.Y - mouseY
-					break
-				}
-			}
+		if ref, ok := sim.bodyAt(mouseX, mouseY); ok {
+			sim.selected = ref
+			sim.startDrag(ref, mouseX, mouseY)
+		} else {
+			sim.panning = true
 		}
 	}
 	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
-		if sim.draggedPlanet != nil {
-			sim.draggedPlanet.IsDragged = false
-			sim.draggedPlanet = nil
-		}
+		sim.endDrag()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyG) {
+		sim.gravityMode = !sim.gravityMode
 	}
-	if sim.draggedPlanet != nil {
-		sim.draggedPlanet.X = mouseX + sim.dragOffsetX
-		sim.draggedPlanet.Y = mouseY + sim.dragOffsetY
-		dx := sim.draggedPlanet.X - sim.sunX
-		dy := sim.draggedPlanet.Y - sim.sunY
-		sim.draggedPlanet.OrbitRadius = math.Hypot(dx, dy)
-		sim.draggedPlanet.Angle = math.Atan2(dy, dx)
+	if sim.dragged.valid() {
+		sim.dragTo(mouseX, mouseY)
 	}
 
 	// Atualiza planetas e suas luas
 	for _, p := range sim.planets {
-		if !p.IsDragged {
+		if !sim.isDragged(bodyRef{planet: p}) {
 			p.Update()
-			p.X = sim.sunX + p.OrbitRadius*math.Cos(p.Angle)
-			p.Y = sim.sunY + p.OrbitRadius*math.Sin(p.Angle)
+		}
+		p.X = sim.sunX + p.OrbitRadius*math.Cos(p.Angle)
+		p.Y = sim.sunY + p.OrbitRadius*math.Sin(p.Angle)
+		for _, m := range p.Moons {
+			if !sim.isDragged(bodyRef{planet: p, moon: m}) {
+				m.Update(planetScreenGM(p))
+			}
+		}
+	}
+
+	sim.updateTrails()
+
+	// A câmera acompanha o corpo selecionado já na nova posição
+	sim.followSelected()
+
+	// Avisa quando luas e cometa cruzam as esferas
+	sim.updateSpheres()
+
+	// Conta o passo na gravação, que pede o próximo frame se for a vez dele
+	sim.tickRecorder()
+
+	// Pôster: F10 (ou -poster, que sai em seguida) desenha a cena deste passo
+	if inpututil.IsKeyJustPressed(ebiten.KeyF10) || sim.posterFile != "" {
+		sim.savePoster(sim.posterFile)
+		if sim.posterFile != "" {
+			return ebiten.Termination
 		}
 	}
 
@@ -560,126 +1615,114 @@ func (sim *Simulation) Update() error {
 
 // Draw é chamado a cada frame para renderizar a cena.
 func (sim *Simulation) Draw(screen *ebiten.Image) {
-	_, _ = screen.Size()
+	sim.drawScene(screen, nil, true)
+	sim.captureFrame(screen)
+}
+
+// drawScene desenha a cena em target. O céu, preso à tela, passa pela câmera
+// sky (nil desenha em pixels da tela) e o resto pela câmera da simulação; com
+// hud os textos são escritos por cima.
+func (sim *Simulation) drawScene(target *ebiten.Image, sky *camera2D, hud bool) {
 	// Fundo espacial
-	screen.Fill(color.RGBA{10, 10, 30, 255})
+	target.Fill(color.RGBA{10, 10, 30, 255})
+	b := &sim.batch
+	b.begin(target)
+	b.cam = sky
 
 	// Desenha as estrelas com brilho oscilante
 	for _, star := range sim.stars {
-		brightness := 128 + 127*math.Sin(star.Phase)
+		brightness := float64(star.Color.A) * (0.5 + 0.5*math.Sin(star.Phase))
 		if brightness < 0 {
 			brightness = 0
 		} else if brightness > 255 {
 			brightness = 255
 		}
-		starColor := color.RGBA{255, 255, 255, uint8(brightness)}
-		drawFilledCircle(screen, star.X, star.Y, 1, starColor)
+		starColor := star.Color
+		starColor.A = uint8(brightness)
+		drawFilledCircle(b, star.X, star.Y, star.Radius, starColor)
 	}
 
+	// Desenha as figuras e os nomes das constelações
+	if sim.showConstellations {
+		lineColor := color.RGBA{90, 140, 220, 120}
+		for _, c := range sim.constellations {
+			for _, seg := range c.Segments {
+				drawThickLine(b, seg[0], seg[1], seg[2], seg[3], 1, lineColor)
+			}
+			b.print(c.Name, int(c.LabelX), int(c.LabelY))
+		}
+	}
+	gravity := "desligado"
+	if sim.gravityMode {
+		gravity = "ligado"
+	}
+	b.print("Roda: zoom | Arrastar: mover corpos ou a vista | F: seguir o corpo clicado | Home: centralizar | G: lançar ao soltar ("+gravity+") | T: rastros | F10: pôster", 10, target.Bounds().Dy()-20)
+
+	// O céu fica preso à tela; daqui em diante tudo está em coordenadas do
+	// mundo e passa pela câmera
+	b.cam = &sim.camera
+
 	// Desenha o cinturão de asteroides
 	for _, a := range sim.asteroids {
 		ax := sim.sunX + a.OrbitRadius*math.Cos(a.Angle)
 		ay := sim.sunY + a.OrbitRadius*math.Sin(a.Angle)
 		asteroidColor := color.RGBA{169, 169, 169, 200}
-		drawFilledCircle(screen, ax, ay, a.Radius, asteroidColor)
+		drawFilledCircle(b, ax, ay, a.Radius, asteroidColor)
 	}
 
 	// Desenha o sol com pulsação
-	drawSunGradient(screen, sim.sunX, sim.sunY, sim.sunRadius, sim.time)
+	drawSunGradient(b, sim.sunX, sim.sunY, sim.sunRadius, sim.time)
 
 	// Desenha as órbitas dos planetas
 	orbitColor := color.RGBA{200, 200, 200, 50}
 	for _, p := range sim.planets {
-		drawCircleOutline(screen, sim.sunX, sim.sunY, p.OrbitRadius, 1, orbitColor)
+		drawCircleOutline(b, sim.sunX, sim.sunY, p.OrbitRadius, 1, orbitColor)
 	}
+	sim.drawTrails(b)
 
 	// Desenha os planetas e, se houver, suas luas
 	for _, p := range sim.planets {
 		// "Halo" do planeta
 		glowColor := color.RGBA{0, 0, 0, 100}
-		drawFilledCircle(screen, p.X, p.Y, float64(p.Radius)*1.4, glowColor)
+		drawFilledCircle(b, p.X, p.Y, float64(p.Radius)*1.4, glowColor)
 		// Planeta com gradiente
-		drawPlanetGradient(screen, p.X, p.Y, float64(p.Radius), p.InnerColor, p.OuterColor)
+		drawPlanetGradient(b, p.X, p.Y, float64(p.Radius), p.InnerColor, p.OuterColor)
 		// Se for Saturno, desenha os anéis
 		if p.Name == "Saturn" {
-			drawSaturnRings(screen, p.X, p.Y, float64(p.Radius))
+			drawSaturnRings(b, p.X, p.Y, float64(p.Radius))
 		}
 		// Desenha as luas, se houver
 		for _, m := range p.Moons {
 			// A posição da lua é relativa ao planeta
 			mx := p.X + m.OrbitRadius*math.Cos(m.Angle)
 			my := p.Y + m.OrbitRadius*math.Sin(m.Angle)
-			drawPlanetGradient(screen, mx, my, m.Radius, m.InnerColor, m.OuterColor)
+			drawPlanetGradient(b, mx, my, m.Radius, m.InnerColor, m.OuterColor)
 		}
 	}
 
-	// Desenha os "raios" de luz que partem do sol (efeito de raios intermitentes)
-	for angleDeg := 0; angleDeg < 360; angleDeg++ {
-		theta := float64(angleDeg) * math.Pi / 180.0
-		dx := math.Cos(theta)
-		dy := math.Sin(theta)
-		ox := sim.sunX
-		oy := sim.sunY
-		bestT := math.MaxFloat64
-		var hitPlanet *Planet
-		var hitX, hitY float64
-		for _, p := range sim.planets {
-			cx := p.X
-			cy := p.Y
-			r := float64(p.Radius)
-			ocx := ox - cx
-			ocy := oy - cy
-			b := 2 * (dx*ocx + dy*ocy)
-			c := ocx*ocx + ocy*ocy - r*r
-			disc := b*b - 4*c
-			if disc < 0 {
-				continue
-			}
-			sqrtDisc := math.Sqrt(disc)
-			t1 := (-b - sqrtDisc) / 2
-			t2 := (-b + sqrtDisc) / 2
-			var t float64
-			if t1 > 0 {
-				t = t1
-			} else {
-				t = t2
-			}
-			if t > 0 && t < bestT {
-				bestT = t
-				hitPlanet = p
-				hitX = ox + dx*t
-				hitY = oy + dy*t
-			}
-		}
-		var endX, endY float64
-		if hitPlanet == nil {
-			endX = ox + dx*1000
-			endY = oy + dy*1000
-		} else {
-			endX = hitX
-			endY = hitY
-		}
-		drawGlowingLine(screen, ox, oy, endX, endY,
-			color.RGBA{255, 255, 200, 60},
-			color.RGBA{255, 255, 170, 120},
-			color.RGBA{255, 255, 150, 200})
-	}
+	// Luz do Sol com as sombras de planetas, luas e asteroides
+	sim.drawSunlight(b, target)
+
+	// Pontos de Lagrange do par escolhido e esferas de influência e de Hill
+	sim.drawLagrange(b)
+	sim.drawSpheres(b)
+	sim.drawDrag(b)
 
 	// Desenha o cometa e sua cauda
 	// Desenha a cauda (linha conectando pontos, com opacidade decrescente)
-	tailLen := len(sim.comet.TailPoints)
-	for i := 0; i < tailLen-1; i++ {
-		alpha := uint8(200 * (1 - float64(i)/float64(tailLen)))
+	sim.comet.Tail.Segments(sim.time, func(p1, p2 [2]float64, age float64) {
+		alpha := uint8(200 * (1 - age))
 		c1 := color.RGBA{255, 255, 255, alpha}
 		c2 := color.RGBA{255, 255, 255, alpha / 2}
-		x1 := sim.comet.TailPoints[i][0]
-		y1 := sim.comet.TailPoints[i][1]
-		x2 := sim.comet.TailPoints[i+1][0]
-		y2 := sim.comet.TailPoints[i+1][1]
-		drawGlowingLine(screen, x1, y1, x2, y2, c1, c1, c2)
-	}
+		drawGlowingLine(b, p1[0], p1[1], p2[0], p2[1], c1, c1, c2)
+	})
 	// Desenha o núcleo do cometa
-	drawFilledCircle(screen, sim.comet.X, sim.comet.Y, 4, color.RGBA{255, 255, 255, 255})
+	drawFilledCircle(b, sim.comet.X, sim.comet.Y, 4, color.RGBA{255, 255, 255, 255})
+
+	if !hud {
+		b.labels = b.labels[:0]
+	}
+	b.flush()
 }
 
 // Layout define o tamanho da janela.
@@ -688,10 +1731,37 @@ func (sim *Simulation) Layout(outsideWidth, outsideHeight int) (int, int) {
 }
 
 func main() {
+	format := flag.String("formato", "avi", "formato da gravação da tecla F9: png, gif ou avi")
+	fps := flag.Float64("fps", 30, "quadros por segundo simulado da gravação")
+	trailSeconds := flag.Float64("rastro", 5, "duração dos rastros da tecla T, em segundos simulados")
+	seed := flag.Int64("semente", 0, "semente das partes aleatórias da cena (0: a hora atual)")
+	resolution := flag.String("resolucao", "", "resolução dos pôsteres da tecla F10, como 7680x4320 (vazio: a da janela)")
+	poster := flag.String("poster", "", "salva um pôster da cena inicial neste PNG e sai")
+	flag.Parse()
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	recordFormat, err := recorder.ParseFormat(*format)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	ebiten.SetWindowTitle("Simulação Avançada do Sistema Solar")
 	ebiten.SetFullscreen(true)
-	sim := NewSimulation()
+	sim := NewSimulation(*seed)
+	sim.recordFormat, sim.recordFPS = recordFormat, *fps
+	sim.trailSeconds = *trailSeconds
+	sim.posterFile = *poster
+	if *resolution != "" {
+		if sim.posterWidth, sim.posterHeight, err = recorder.ParseResolution(*resolution); err != nil {
+			log.Fatal(err)
+		}
+	}
 	if err := ebiten.RunGame(sim); err != nil {
 		log.Fatal(err)
 	}
+	// Fecha a gravação que ficou aberta ao sair
+	if sim.recorder != nil {
+		sim.toggleRecording()
+	}
 }
@@ -0,0 +1,146 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"math"
+	"os"
+)
+
+// jpegQuality é a qualidade dos quadros do AVI.
+const jpegQuality = 90
+
+// aviWriter grava um AVI (RIFF) com um fluxo de vídeo MJPEG. Os quadros vão
+// direto para a lista movi; o índice idx1 e os totais do cabeçalho são
+// escritos no Close.
+type aviWriter struct {
+	f     *os.File
+	fps   float64
+	size  image.Point
+	index []aviIndexEntry
+	movi  int64 // posição do identificador "movi"
+	end   int64
+	max   uint32 // maior quadro, para o tamanho sugerido de buffer
+	buf   bytes.Buffer
+}
+
+type aviIndexEntry struct {
+	offset, size uint32
+}
+
+// Posições no cabeçalho dos campos corrigidos no Close.
+const (
+	aviTotalFrames = 48  // avih.dwTotalFrames
+	aviAvihBuffer  = 60  // avih.dwSuggestedBufferSize
+	aviStrhLength  = 140 // strh.dwLength
+	aviStrhBuffer  = 144 // strh.dwSuggestedBufferSize
+)
+
+func newAVIWriter(path string, fps float64) (*aviWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &aviWriter{f: f, fps: fps}, nil
+}
+
+// le monta uma sequência de campos little-endian.
+func le(fields ...any) []byte {
+	var b bytes.Buffer
+	for _, v := range fields {
+		if s, ok := v.(string); ok {
+			b.WriteString(s)
+			continue
+		}
+		binary.Write(&b, binary.LittleEndian, v)
+	}
+	return b.Bytes()
+}
+
+// writeHeader escreve o RIFF, a lista hdrl e a abertura da lista movi, com
+// os totais zerados; o tamanho do vídeo é o do primeiro quadro.
+func (a *aviWriter) writeHeader(size image.Point) error {
+	a.size = size
+	w, h := uint32(size.X), uint32(size.Y)
+	usPerFrame := uint32(math.Round(1e6 / a.fps))
+	rate := uint32(math.Round(a.fps * 1000))
+	header := le(
+		"RIFF", uint32(0), "AVI ",
+		"LIST", uint32(192), "hdrl",
+		"avih", uint32(56), usPerFrame, uint32(0), uint32(0), uint32(0x10), // AVIF_HASINDEX
+		uint32(0), uint32(0), uint32(1), uint32(0), w, h, [4]uint32{},
+		"LIST", uint32(116), "strl",
+		"strh", uint32(56), "vids", "MJPG", uint32(0), uint16(0), uint16(0), uint32(0),
+		uint32(1000), rate, uint32(0), uint32(0), uint32(0), int32(-1), uint32(0),
+		[4]uint16{0, 0, uint16(w), uint16(h)},
+		"strf", uint32(40), uint32(40), int32(w), int32(h), uint16(1), uint16(24), "MJPG",
+		w*h*3, int32(0), int32(0), uint32(0), uint32(0),
+		"LIST", uint32(0), "movi",
+	)
+	a.movi = int64(len(header)) - 4
+	a.end = int64(len(header))
+	_, err := a.f.Write(header)
+	return err
+}
+
+func (a *aviWriter) WriteFrame(img image.Image) error {
+	if a.end == 0 {
+		if err := a.writeHeader(img.Bounds().Size()); err != nil {
+			return err
+		}
+	}
+	a.buf.Reset()
+	if err := jpeg.Encode(&a.buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return err
+	}
+	n := uint32(a.buf.Len())
+	a.index = append(a.index, aviIndexEntry{offset: uint32(a.end - a.movi), size: n})
+	a.max = max(a.max, n)
+	chunk := le("00dc", n)
+	chunk = append(chunk, a.buf.Bytes()...)
+	if n%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	if _, err := a.f.Write(chunk); err != nil {
+		return err
+	}
+	a.end += int64(len(chunk))
+	return nil
+}
+
+// Close escreve o índice e corrige os tamanhos e totais do cabeçalho.
+func (a *aviWriter) Close() error {
+	if a.end == 0 {
+		a.f.Close()
+		os.Remove(a.f.Name())
+		return errNoFrames
+	}
+	idx := le("idx1", uint32(16*len(a.index)))
+	for _, e := range a.index {
+		idx = append(idx, le("00dc", uint32(0x10), e.offset, e.size)...)
+	}
+	fixes := []struct {
+		at    int64
+		value uint32
+	}{
+		{4, uint32(a.end + int64(len(idx)) - 8)},
+		{aviTotalFrames, uint32(len(a.index))},
+		{aviAvihBuffer, a.max + 8},
+		{aviStrhLength, uint32(len(a.index))},
+		{aviStrhBuffer, a.max + 8},
+		{a.movi - 4, uint32(a.end - a.movi)},
+	}
+	_, err := a.f.Write(idx)
+	for _, fix := range fixes {
+		if err == nil {
+			_, err = a.f.WriteAt(le(fix.value), fix.at)
+		}
+	}
+	if err != nil {
+		a.f.Close()
+		return err
+	}
+	return a.f.Close()
+}
@@ -0,0 +1,117 @@
+package recorder
+
+import (
+	"bufio"
+	"compress/lzw"
+	"encoding/binary"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"math"
+	"os"
+)
+
+// gifWriter grava um GIF animado quadro a quadro, sem guardar os quadros na
+// memória como gif.EncodeAll. Todos os quadros usam a paleta Plan 9 como
+// tabela global de cores, com pontilhado de Floyd-Steinberg.
+type gifWriter struct {
+	f     *os.File
+	w     *bufio.Writer
+	delay uint16 // centésimos de segundo por quadro
+	size  image.Point
+	pal   *image.Paletted
+}
+
+func newGIFWriter(path string, fps float64) (*gifWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	// O GIF só conta o tempo em centésimos de segundo
+	delay := uint16(max(2, math.Round(100/fps)))
+	return &gifWriter{f: f, w: bufio.NewWriter(f), delay: delay}, nil
+}
+
+// writeHeader escreve o cabeçalho, a paleta global e o laço infinito; o
+// tamanho do GIF é o do primeiro quadro.
+func (g *gifWriter) writeHeader(size image.Point) {
+	g.size = size
+	g.pal = image.NewPaletted(image.Rectangle{Max: size}, palette.Plan9)
+	g.w.WriteString("GIF89a")
+	binary.Write(g.w, binary.LittleEndian, [2]uint16{uint16(size.X), uint16(size.Y)})
+	g.w.Write([]byte{0xF7, 0, 0}) // tabela global de 256 cores
+	for _, c := range palette.Plan9 {
+		r, gr, b, _ := c.RGBA()
+		g.w.Write([]byte{byte(r >> 8), byte(gr >> 8), byte(b >> 8)})
+	}
+	g.w.Write([]byte{0x21, 0xFF, 0x0B})
+	g.w.WriteString("NETSCAPE2.0")
+	g.w.Write([]byte{0x03, 0x01, 0, 0, 0})
+}
+
+func (g *gifWriter) WriteFrame(img image.Image) error {
+	if g.pal == nil {
+		g.writeHeader(img.Bounds().Size())
+	}
+	draw.FloydSteinberg.Draw(g.pal, g.pal.Bounds(), img, img.Bounds().Min)
+
+	// Extensão de controle (atraso do quadro) e descritor da imagem
+	g.w.Write([]byte{0x21, 0xF9, 0x04, 0x04, byte(g.delay), byte(g.delay >> 8), 0, 0})
+	g.w.WriteByte(0x2C)
+	binary.Write(g.w, binary.LittleEndian, [4]uint16{0, 0, uint16(g.size.X), uint16(g.size.Y)})
+	g.w.WriteByte(0)
+
+	// Dados LZW em blocos de até 255 bytes
+	g.w.WriteByte(8)
+	blocks := &gifBlocks{w: g.w}
+	lw := lzw.NewWriter(blocks, lzw.LSB, 8)
+	if _, err := lw.Write(g.pal.Pix); err != nil {
+		return err
+	}
+	if err := lw.Close(); err != nil {
+		return err
+	}
+	blocks.flush()
+	return g.w.WriteByte(0)
+}
+
+func (g *gifWriter) Close() error {
+	if g.pal == nil {
+		g.f.Close()
+		os.Remove(g.f.Name())
+		return errNoFrames
+	}
+	g.w.WriteByte(0x3B)
+	if err := g.w.Flush(); err != nil {
+		g.f.Close()
+		return err
+	}
+	return g.f.Close()
+}
+
+// gifBlocks divide o fluxo LZW nos sub-blocos do GIF.
+type gifBlocks struct {
+	w   *bufio.Writer
+	buf [255]byte
+	n   int
+}
+
+func (b *gifBlocks) Write(p []byte) (int, error) {
+	for _, c := range p {
+		b.buf[b.n] = c
+		b.n++
+		if b.n == len(b.buf) {
+			b.flush()
+		}
+	}
+	return len(p), nil
+}
+
+func (b *gifBlocks) flush() {
+	if b.n == 0 {
+		return
+	}
+	b.w.WriteByte(byte(b.n))
+	b.w.Write(b.buf[:b.n])
+	b.n = 0
+}
@@ -0,0 +1,74 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"os"
+	"time"
+)
+
+// Metadata é um par chave/valor gravado num bloco iTXt (texto UTF-8) do PNG.
+// A chave deve ter de 1 a 79 caracteres latinos.
+type Metadata struct {
+	Key, Value string
+}
+
+// pngHeaderSize é o tamanho da assinatura mais o bloco IHDR, depois do qual
+// os blocos de texto são inseridos.
+const pngHeaderSize = 8 + 4 + 4 + 13 + 4
+
+// PosterName é o nome padrão do pôster salvo em t com F10 (nos visualizadores
+// raylib o F12 é a captura de tela do próprio raylib).
+func PosterName(t time.Time) string {
+	return "poster-" + t.Format("20060102-150405") + ".png"
+}
+
+// ParseResolution interpreta uma resolução no formato "LARGURAxALTURA".
+func ParseResolution(s string) (int, int, error) {
+	var w, h int
+	if _, err := fmt.Sscanf(s, "%dx%d", &w, &h); err != nil || w <= 0 || h <= 0 {
+		return 0, 0, fmt.Errorf("resolução inválida %q (use por exemplo 7680x4320)", s)
+	}
+	return w, h, nil
+}
+
+// WritePNG grava a imagem num arquivo PNG.
+func WritePNG(path string, img image.Image) error {
+	return WritePNGMetadata(path, img, nil)
+}
+
+// WritePNGMetadata grava a imagem num arquivo PNG com os metadados em blocos
+// iTXt logo depois do cabeçalho, onde os visualizadores de imagem os leem.
+func WritePNGMetadata(path string, img image.Image, meta []Metadata) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+	data := buf.Bytes()
+	var out bytes.Buffer
+	out.Write(data[:pngHeaderSize])
+	for _, m := range meta {
+		// palavra-chave, sem compressão, sem idioma nem tradução, texto
+		body := append([]byte(m.Key), 0, 0, 0, 0, 0)
+		body = append(body, m.Value...)
+		writeChunk(&out, "iTXt", body)
+	}
+	out.Write(data[pngHeaderSize:])
+	return os.WriteFile(path, out.Bytes(), 0o644)
+}
+
+// writeChunk escreve um bloco PNG: tamanho, tipo, dados e CRC do tipo com os
+// dados.
+func writeChunk(w *bytes.Buffer, kind string, body []byte) {
+	binary.Write(w, binary.BigEndian, uint32(len(body)))
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(kind))
+	crc.Write(body)
+	w.WriteString(kind)
+	w.Write(body)
+	binary.Write(w, binary.BigEndian, crc.Sum32())
+}
@@ -0,0 +1,71 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWritePNGMetadata(t *testing.T) {
+	meta := []Metadata{
+		{"Software", "Sistema Solar"},
+		{"Simulation Date", "2024-04-08 18:17 UTC"},
+		{"Camera", "posição (0.00, 300.00, 600.00), fov 45.0°"},
+		{"Seed", "42"},
+	}
+	path := filepath.Join(t.TempDir(), "poster.png")
+	want := color.RGBA{10, 20, 30, 255}
+	if err := WritePNGMetadata(path, testFrame(8, 4, want), meta); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// O arquivo continua um PNG válido, com a imagem intacta
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := color.RGBAModel.Convert(img.At(3, 2)); got != want {
+		t.Errorf("pixel %v, esperado %v", got, want)
+	}
+
+	// Os blocos iTXt vêm logo depois do IHDR, na ordem dada
+	var got []Metadata
+	for at := 8; at < len(data); {
+		n := int(binary.BigEndian.Uint32(data[at:]))
+		kind, body := string(data[at+4:at+8]), data[at+8:at+8+n]
+		if kind == "iTXt" {
+			key, rest, _ := bytes.Cut(body, []byte{0})
+			got = append(got, Metadata{string(key), string(rest[4:])})
+		} else if kind != "IHDR" && len(got) < len(meta) {
+			t.Fatalf("bloco %s antes dos metadados", kind)
+		}
+		at += 12 + n
+	}
+	if len(got) != len(meta) {
+		t.Fatalf("metadados %v, esperados %v", got, meta)
+	}
+	for i := range meta {
+		if got[i] != meta[i] {
+			t.Errorf("metadado %d = %v, esperado %v", i, got[i], meta[i])
+		}
+	}
+}
+
+func TestParseResolution(t *testing.T) {
+	if w, h, err := ParseResolution("7680x4320"); err != nil || w != 7680 || h != 4320 {
+		t.Errorf("ParseResolution(\"7680x4320\") = %d, %d, %v", w, h, err)
+	}
+	for _, s := range []string{"", "7680", "0x4320", "-1x10", "ax b"} {
+		if _, _, err := ParseResolution(s); err == nil {
+			t.Errorf("ParseResolution(%q) não deu erro", s)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Package recorder grava os quadros dos visualizadores em disco, em Go puro
+// (sem ffmpeg): uma sequência de PNGs, um GIF animado ou um AVI com quadros
+// JPEG (MJPEG). Os quadros seguem o relógio da simulação e não o tempo real,
+// então uma gravação lenta não perde quadros. Também grava capturas em PNG
+// com metadados (data da simulação, câmera, semente).
+package recorder
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Format é o formato de saída da gravação.
+type Format int
+
+const (
+	PNGSequence Format = iota // um arquivo PNG por quadro, numa pasta
+	GIF                       // GIF animado com a paleta Plan 9
+	MJPEG                     // AVI com um JPEG por quadro
+)
+
+var formatNames = [...]string{"png", "gif", "avi"}
+
+func (f Format) String() string {
+	return formatNames[f]
+}
+
+// ParseFormat interpreta o nome do formato usado nas opções de linha de
+// comando: "png", "gif" ou "avi".
+func ParseFormat(name string) (Format, error) {
+	for i, n := range formatNames {
+		if n == name {
+			return Format(i), nil
+		}
+	}
+	return 0, fmt.Errorf("formato de gravação desconhecido %q (use png, gif ou avi)", name)
+}
+
+// DefaultName devolve o nome da gravação iniciada em t: uma pasta para a
+// sequência de PNGs ou um arquivo com a extensão do formato.
+func DefaultName(f Format, t time.Time) string {
+	name := "gravacao-" + t.Format("20060102-150405")
+	if f == PNGSequence {
+		return name
+	}
+	return name + "." + f.String()
+}
+
+// errNoFrames é devolvido por Close quando nenhum quadro foi gravado: sem o
+// primeiro quadro não se sabe o tamanho do vídeo, e o arquivo é apagado.
+var errNoFrames = errors.New("gravação sem quadros")
+
+// frameWriter grava quadros num formato; Close termina o arquivo.
+type frameWriter interface {
+	WriteFrame(img image.Image) error
+	Close() error
+}
+
+// Recorder grava um quadro a cada every passos da simulação. Tick deve ser
+// chamado uma vez por passo, e Add só quando Tick pedir o quadro, para que
+// a leitura da tela seja feita apenas quando necessário.
+type Recorder struct {
+	Path   string
+	FPS    float64 // quadros por segundo simulado do vídeo
+	every  int
+	step   int
+	frames int
+	out    frameWriter
+}
+
+// Start começa uma gravação em path a fps quadros por segundo, para uma
+// simulação de stepsPerSecond passos por segundo simulado. O fps real é
+// arredondado para um divisor de stepsPerSecond.
+func Start(path string, f Format, fps, stepsPerSecond float64) (*Recorder, error) {
+	every := max(1, int(math.Round(stepsPerSecond/fps)))
+	r := &Recorder{Path: path, FPS: stepsPerSecond / float64(every), every: every}
+	var err error
+	switch f {
+	case PNGSequence:
+		err = os.MkdirAll(path, 0o755)
+		r.out = &pngSequence{dir: path}
+	case GIF:
+		r.out, err = newGIFWriter(path, r.FPS)
+	case MJPEG:
+		r.out, err = newAVIWriter(path, r.FPS)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Tick avança um passo da simulação e informa se o quadro deste passo deve
+// ser gravado.
+func (r *Recorder) Tick() bool {
+	due := r.step%r.every == 0
+	r.step++
+	return due
+}
+
+// Add grava o quadro.
+func (r *Recorder) Add(img image.Image) error {
+	if err := r.out.WriteFrame(img); err != nil {
+		return err
+	}
+	r.frames++
+	return nil
+}
+
+// Frames é o número de quadros gravados até agora.
+func (r *Recorder) Frames() int {
+	return r.frames
+}
+
+// Close termina a gravação.
+func (r *Recorder) Close() error {
+	return r.out.Close()
+}
+
+// pngSequence grava quadro-00001.png, quadro-00002.png, ... na pasta dir.
+type pngSequence struct {
+	dir string
+	n   int
+}
+
+func (s *pngSequence) WriteFrame(img image.Image) error {
+	s.n++
+	return WritePNG(filepath.Join(s.dir, fmt.Sprintf("quadro-%05d.png", s.n)), img)
+}
+
+func (s *pngSequence) Close() error {
+	return nil
+}
@@ -0,0 +1,201 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testFrame é um quadro w x h de uma cor só.
+func testFrame(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+var testColors = []color.RGBA{{255, 0, 0, 255}, {0, 255, 0, 255}, {0, 0, 255, 255}}
+
+// record grava um quadro de cada cor de testColors e devolve o caminho.
+func record(t *testing.T, f Format, name string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	rec, err := Start(path, f, 30, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range testColors {
+		if err := rec.Add(testFrame(40, 30, c)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Frames() != len(testColors) {
+		t.Errorf("Frames = %d, esperado %d", rec.Frames(), len(testColors))
+	}
+	return path
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range []Format{PNGSequence, GIF, MJPEG} {
+		got, err := ParseFormat(f.String())
+		if err != nil || got != f {
+			t.Errorf("ParseFormat(%q) = %v, %v", f.String(), got, err)
+		}
+	}
+	if _, err := ParseFormat("mp4"); err == nil {
+		t.Error("ParseFormat(\"mp4\") não deu erro")
+	}
+}
+
+func TestTick(t *testing.T) {
+	tests := []struct {
+		fps, steps float64
+		want       []bool
+	}{
+		{30, 60, []bool{true, false, true, false}},
+		{60, 60, []bool{true, true, true}},
+		{120, 60, []bool{true, true}}, // no máximo um quadro por passo
+		{20, 60, []bool{true, false, false, true}},
+	}
+	for _, tt := range tests {
+		rec, err := Start(t.TempDir(), PNGSequence, tt.fps, tt.steps)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, want := range tt.want {
+			if got := rec.Tick(); got != want {
+				t.Errorf("fps %v: Tick %d = %v, esperado %v", tt.fps, i, got, want)
+			}
+		}
+	}
+}
+
+func TestGIFRoundTrip(t *testing.T) {
+	f, err := os.Open(record(t, GIF, "teste.gif"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Image) != len(testColors) {
+		t.Fatalf("%d quadros, esperados %d", len(g.Image), len(testColors))
+	}
+	if g.Config.Width != 40 || g.Config.Height != 30 {
+		t.Errorf("tamanho %dx%d, esperado 40x30", g.Config.Width, g.Config.Height)
+	}
+	if g.LoopCount != 0 {
+		t.Errorf("LoopCount = %d, esperado 0 (laço infinito)", g.LoopCount)
+	}
+	for i, img := range g.Image {
+		if g.Delay[i] != 3 {
+			t.Errorf("quadro %d: atraso %d, esperado 3 centésimos", i, g.Delay[i])
+		}
+		r, gr, b, _ := img.At(20, 15).RGBA()
+		want := testColors[i]
+		if uint8(r>>8) != want.R || uint8(gr>>8) != want.G || uint8(b>>8) != want.B {
+			t.Errorf("quadro %d: cor (%d, %d, %d), esperado %v", i, r>>8, gr>>8, b>>8, want)
+		}
+	}
+}
+
+func TestAVIHeader(t *testing.T) {
+	data, err := os.ReadFile(record(t, MJPEG, "teste.avi"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	u32 := func(at int) uint32 { return binary.LittleEndian.Uint32(data[at:]) }
+	if string(data[0:4]) != "RIFF" || string(data[8:12]) != "AVI " {
+		t.Fatalf("cabeçalho %q, esperado RIFF/AVI", data[:12])
+	}
+	if got := u32(4); int(got) != len(data)-8 {
+		t.Errorf("tamanho do RIFF %d, esperado %d", got, len(data)-8)
+	}
+	if got := u32(aviTotalFrames); got != uint32(len(testColors)) {
+		t.Errorf("avih.dwTotalFrames = %d, esperado %d", got, len(testColors))
+	}
+	if got := u32(aviStrhLength); got != uint32(len(testColors)) {
+		t.Errorf("strh.dwLength = %d, esperado %d", got, len(testColors))
+	}
+	if got := u32(32); got != 33333 {
+		t.Errorf("avih.dwMicroSecPerFrame = %d, esperado 33333", got)
+	}
+	if w, h := u32(64), u32(68); w != 40 || h != 30 {
+		t.Errorf("tamanho %dx%d, esperado 40x30", w, h)
+	}
+
+	// O índice aponta para quadros JPEG que decodificam na cor gravada
+	movi := bytes.Index(data, []byte("movi"))
+	idx := bytes.LastIndex(data, []byte("idx1"))
+	if movi < 0 || idx < 0 {
+		t.Fatal("listas movi ou idx1 ausentes")
+	}
+	if got := int(u32(movi - 4)); got != idx-movi {
+		t.Errorf("tamanho da lista movi %d, esperado %d", got, idx-movi)
+	}
+	if n := int(u32(idx + 4)); n != 16*len(testColors) {
+		t.Fatalf("idx1 com %d bytes, esperados %d", n, 16*len(testColors))
+	}
+	for i, want := range testColors {
+		entry := idx + 8 + 16*i
+		offset, size := int(u32(entry+8)), int(u32(entry+12))
+		chunk := data[movi+offset:]
+		if string(chunk[:4]) != "00dc" {
+			t.Fatalf("quadro %d: bloco %q, esperado 00dc", i, chunk[:4])
+		}
+		img, err := jpeg.Decode(bytes.NewReader(chunk[8 : 8+size]))
+		if err != nil {
+			t.Fatalf("quadro %d: %v", i, err)
+		}
+		r, g, b, _ := img.At(20, 15).RGBA()
+		if d := absDiff(r>>8, uint32(want.R)) + absDiff(g>>8, uint32(want.G)) + absDiff(b>>8, uint32(want.B)); d > 24 {
+			t.Errorf("quadro %d: cor (%d, %d, %d), esperado %v", i, r>>8, g>>8, b>>8, want)
+		}
+	}
+}
+
+func absDiff(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func TestPNGSequence(t *testing.T) {
+	dir := record(t, PNGSequence, "quadros")
+	names, err := filepath.Glob(filepath.Join(dir, "quadro-*.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != len(testColors) {
+		t.Errorf("%d arquivos, esperados %d: %v", len(names), len(testColors), names)
+	}
+}
+
+func TestCloseWithoutFrames(t *testing.T) {
+	for _, f := range []Format{GIF, MJPEG} {
+		path := filepath.Join(t.TempDir(), "vazio."+f.String())
+		rec, err := Start(path, f, 30, 60)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rec.Close(); err != errNoFrames {
+			t.Errorf("%s: Close = %v, esperado errNoFrames", f, err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s: o arquivo vazio não foi apagado", f)
+		}
+	}
+}
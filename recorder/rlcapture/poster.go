@@ -0,0 +1,71 @@
+// Package rlcapture liga o recorder aos visualizadores raylib: grava a tela
+// em vídeo (tecla F9) e desenha pôsteres em alta resolução, bloco a bloco.
+package rlcapture
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"unsafe"
+
+	"go-playground/recorder"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// posterTile é o lado dos blocos em que o pôster é desenhado; cada bloco é
+// uma textura fora da tela, então o pôster pode passar do tamanho da janela
+// e do limite de texturas da placa de vídeo.
+const posterTile = 1024
+
+// RenderPoster desenha a cena vista pela câmera numa imagem width x height,
+// bloco a bloco. Cada bloco usa a fatia correspondente do frustum da câmera
+// (projeção fora do eixo), de modo que os blocos se emendam sem costura.
+// background, se não for nil, desenha o fundo 2D do bloco, que ocupa a
+// fração part do pôster; draw desenha a cena 3D.
+func RenderPoster(width, height int, camera rl.Camera3D, clear rl.Color, background func(part rl.Rectangle, w, h float32), draw func()) *image.RGBA {
+	near, far := rl.GetCullDistanceNear(), rl.GetCullDistanceFar()
+	top := near * math.Tan(float64(camera.Fovy)*math.Pi/360)
+	right := top * float64(width) / float64(height)
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y0 := 0; y0 < height; y0 += posterTile {
+		for x0 := 0; x0 < width; x0 += posterTile {
+			tw, th := min(posterTile, width-x0), min(posterTile, height-y0)
+			target := rl.LoadRenderTexture(int32(tw), int32(th))
+			rl.BeginTextureMode(target)
+			rl.ClearBackground(clear)
+			if background != nil {
+				part := rl.NewRectangle(float32(x0)/float32(width), float32(y0)/float32(height),
+					float32(tw)/float32(width), float32(th)/float32(height))
+				background(part, float32(tw), float32(th))
+			}
+			rl.BeginMode3D(camera)
+			fx := func(x int) float32 { return float32(-right + 2*right*float64(x)/float64(width)) }
+			fy := func(y int) float32 { return float32(top - 2*top*float64(y)/float64(height)) }
+			rl.SetMatrixProjection(rl.MatrixFrustum(fx(x0), fx(x0+tw), fy(y0+th), fy(y0), float32(near), float32(far)))
+			draw()
+			rl.EndMode3D()
+			rl.EndTextureMode()
+
+			// A textura volta de cabeça para baixo; o alfa é forçado a opaco
+			shot := rl.LoadImageFromTexture(target.Texture)
+			pix := unsafe.Slice((*byte)(shot.Data), tw*th*4)
+			for y := 0; y < th; y++ {
+				row := img.Pix[img.PixOffset(x0, y0+y):]
+				copy(row[:tw*4], pix[(th-1-y)*tw*4:])
+				for x := 3; x < tw*4; x += 4 {
+					row[x] = 255
+				}
+			}
+			rl.UnloadImage(shot)
+			rl.UnloadRenderTexture(target)
+		}
+	}
+	return img
+}
+
+// CameraMetadata descreve a câmera para os metadados do pôster.
+func CameraMetadata(camera rl.Camera3D) recorder.Metadata {
+	return recorder.Metadata{Key: "Camera", Value: fmt.Sprintf("posição (%.2f, %.2f, %.2f), alvo (%.2f, %.2f, %.2f), fov %.1f°",
+		camera.Position.X, camera.Position.Y, camera.Position.Z, camera.Target.X, camera.Target.Y, camera.Target.Z, camera.Fovy)}
+}
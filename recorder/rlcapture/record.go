@@ -0,0 +1,66 @@
+package rlcapture
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"time"
+	"unsafe"
+
+	"go-playground/recorder"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// StepsPerSecond é o número de passos da simulação por segundo simulado; o
+// laço principal dá um passo por quadro desenhado, então a gravação segue o
+// relógio da simulação mesmo que os quadros fiquem lentos.
+const StepsPerSecond = 60
+
+// ToggleRecording começa uma gravação ou termina a atual (tecla F9).
+func ToggleRecording(rec *recorder.Recorder, format recorder.Format, fps float64) *recorder.Recorder {
+	if rec != nil {
+		if err := rec.Close(); err != nil {
+			log.Print(err)
+		} else {
+			log.Printf("gravação salva em %s (%d quadros)", rec.Path, rec.Frames())
+		}
+		return nil
+	}
+	rec, err := recorder.Start(recorder.DefaultName(format, time.Now()), format, fps, StepsPerSecond)
+	if err != nil {
+		log.Print(err)
+		return nil
+	}
+	return rec
+}
+
+// CaptureScreen copia a tela para uma imagem Go. Deve ser chamada depois de
+// desenhar o quadro e antes de rl.EndDrawing; o lote pendente do rlgl (o
+// texto e os painéis 2D) é enviado antes, como faz a captura do EndDrawing.
+func CaptureScreen() *image.RGBA {
+	rl.DrawRenderBatchActive()
+	shot := rl.LoadImageFromScreen()
+	defer rl.UnloadImage(shot)
+	img := image.NewRGBA(image.Rect(0, 0, int(shot.Width), int(shot.Height)))
+	copy(img.Pix, unsafe.Slice((*byte)(shot.Data), len(img.Pix)))
+	return img
+}
+
+// RecordFrame grava o quadro, se for a vez deste passo, e depois marca a
+// gravação na tela (a marca não entra no vídeo). Devolve a gravação, ou nil
+// se ela falhou.
+func RecordFrame(rec *recorder.Recorder, screenWidth int32) *recorder.Recorder {
+	if rec == nil {
+		return nil
+	}
+	if rec.Tick() {
+		if err := rec.Add(CaptureScreen()); err != nil {
+			log.Print(err)
+			return ToggleRecording(rec, 0, 0)
+		}
+	}
+	text := fmt.Sprintf("GRAVANDO %s (%d quadros) | F9: parar", rec.Path, rec.Frames())
+	rl.DrawText(text, (screenWidth-rl.MeasureText(text, 20))/2, 10, 20, rl.Red)
+	return rec
+}
@@ -1,16 +1,34 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"log"
 	"math"
 	"math/rand"
+	"os"
+	"sort"
 	"time"
 
+	"go-playground/astro"
+	"go-playground/campath"
+	"go-playground/catalog"
+	"go-playground/recorder"
+	"go-playground/recorder/rlcapture"
+	"go-playground/trail"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 // Definição de uma cor Silver (já que rl.Silver não está definida)
 var Silver = rl.NewColor(192, 192, 192, 255)
 
+// Arquivo do catálogo de estrelas (formato BSC5) e raio da esfera celeste da cena.
+const (
+	starCatalogFile       = "data/bsc5.dat"
+	celestialSphereRadius = 700
+)
+
 // ─────────────────────────────────────────────
 // Estruturas da simulação (a lógica permanece semelhante, com mais objetos)
 type Star struct {
@@ -18,6 +36,9 @@ type Star struct {
 	Phase          float64
 	Speed          float64
 	BaseBrightness int
+	Color          rl.Color
+	Size           float32
+	Twinkle        bool // apenas as estrelas aleatórias (sem catálogo) cintilam
 }
 
 type Moon struct {
@@ -45,12 +66,17 @@ type Asteroid struct {
 	Radius      float32
 }
 
+// cometTailSeconds é a duração do rastro do cometa (20 frames).
+const cometTailSeconds = 20.0 / 60
+
+// cometRadius é o raio do núcleo do cometa na cena.
+const cometRadius = 4
+
 type Comet struct {
-	Position      rl.Vector3
-	Angle         float64
-	Speed         float64
-	TailPoints    []rl.Vector3
-	TailMaxLength int
+	Position rl.Vector3
+	Angle    float64
+	Speed    float64
+	Tail     *trail.Trail[rl.Vector3] // em segundos de Time
 }
 
 type Simulation struct {
@@ -59,17 +85,22 @@ type Simulation struct {
 	Stars     []Star
 	Asteroids []Asteroid // Inclui cinturão principal e o Kuiper Belt
 	Comet     Comet
-	Time      float64
-	CamAngle  float64 // Para movimentação dinâmica da câmera
+	Time      float64    // relógio da simulação (s), que guia o caminho da câmera
+	Seed      int64      // semente dos asteroides e das estrelas
+	rng       *rand.Rand // gerador criado com Seed (rand.Seed não fixa mais a sequência global)
+
+	occluderCapLogged bool // o aviso de sombras descartadas já foi dado
 }
 
-// NewSimulation cria e inicializa os corpos celestes
-func NewSimulation() *Simulation {
+// NewSimulation cria e inicializa os corpos celestes; a semente fixa as
+// partes aleatórias da cena.
+func NewSimulation(seed int64) *Simulation {
 	sim := &Simulation{
+		rng:       rand.New(rand.NewSource(seed)),
+		Seed:      seed,
 		SunRadius: 40,
 		Planets:   make([]*Planet, 0),
 		Time:      0,
-		CamAngle:  0,
 	}
 
 	// Planetas – cada um com seus parâmetros
@@ -181,61 +212,95 @@ func NewSimulation() *Simulation {
 		Color:       rl.Brown,
 	})
 
-	// Estrelas distribuídas numa casca esférica distante
-	starCount := 200
-	sim.Stars = make([]Star, starCount)
-	for i := 0; i < starCount; i++ {
-		r := 600 + rand.Float64()*200
-		theta := rand.Float64() * 2 * math.Pi
-		phi := rand.Float64() * math.Pi
-		x := r * math.Sin(phi) * math.Cos(theta)
-		y := r * math.Cos(phi)
-		z := r * math.Sin(phi) * math.Sin(theta)
-		sim.Stars[i] = Star{
-			Position:       rl.NewVector3(float32(x), float32(y), float32(z)),
-			Phase:          rand.Float64() * 2 * math.Pi,
-			Speed:          0.005 + rand.Float64()*0.005,
-			BaseBrightness: 100 + rand.Intn(155),
-		}
+	// Estrelas do catálogo BSC na esfera celeste; sem o arquivo, volta ao
+	// campo aleatório numa casca esférica distante
+	if cat, err := catalog.LoadBSC(starCatalogFile); err == nil {
+		sim.Stars = catalogStars(cat)
+	} else {
+		log.Printf("catálogo de estrelas indisponível (%v); usando estrelas aleatórias", err)
+		sim.Stars = randomStars(sim.rng, 200)
 	}
 
 	// Cinturão de Asteroides (principal e Kuiper Belt)
 	asteroidCount := 150
 	sim.Asteroids = make([]Asteroid, 0, asteroidCount+50)
 	for i := 0; i < asteroidCount; i++ {
-		orbitRadius := 210 + rand.Float64()*30
+		orbitRadius := 210 + sim.rng.Float64()*30
 		sim.Asteroids = append(sim.Asteroids, Asteroid{
 			OrbitRadius: orbitRadius,
-			Angle:       rand.Float64() * 2 * math.Pi,
-			OrbitSpeed:  0.008 + rand.Float64()*0.004,
-			Radius:      1 + float32(rand.Float64()*1.5),
+			Angle:       sim.rng.Float64() * 2 * math.Pi,
+			OrbitSpeed:  0.008 + sim.rng.Float64()*0.004,
+			Radius:      1 + float32(sim.rng.Float64()*1.5),
 		})
 	}
 	// Kuiper Belt (asteroides extras além de Plutão)
 	kuiperCount := 50
 	for i := 0; i < kuiperCount; i++ {
-		orbitRadius := 500 + rand.Float64()*100
+		orbitRadius := 500 + sim.rng.Float64()*100
 		sim.Asteroids = append(sim.Asteroids, Asteroid{
 			OrbitRadius: orbitRadius,
-			Angle:       rand.Float64() * 2 * math.Pi,
-			OrbitSpeed:  0.003 + rand.Float64()*0.002,
-			Radius:      0.5 + float32(rand.Float64()*1.0),
+			Angle:       sim.rng.Float64() * 2 * math.Pi,
+			OrbitSpeed:  0.003 + sim.rng.Float64()*0.002,
+			Radius:      0.5 + float32(sim.rng.Float64()*1.0),
 		})
 	}
 
 	// Cometa (com rastro)
 	sim.Comet = Comet{
-		Position:      rl.NewVector3(-50, 0, -50),
-		Angle:         math.Pi / 4,
-		Speed:         4.0,
-		TailPoints:    make([]rl.Vector3, 0),
-		TailMaxLength: 20,
+		Position: rl.NewVector3(-50, 0, -50),
+		Angle:    math.Pi / 4,
+		Speed:    4.0,
+		Tail:     trail.New[rl.Vector3](cometTailSeconds),
 	}
-
-	rand.Seed(time.Now().UnixNano())
 	return sim
 }
 
+// catalogStars posiciona as estrelas do catálogo na esfera celeste da cena,
+// com tamanho e cor derivados da magnitude e do índice B–V.
+func catalogStars(cat []catalog.Star) []Star {
+	stars := make([]Star, 0, len(cat))
+	for _, s := range cat {
+		dir := astro.EquatorialToEcliptic(s.Direction()).Scale(celestialSphereRadius)
+		stars = append(stars, Star{
+			Position:       eclipticToScene(dir),
+			BaseBrightness: int(s.Brightness()),
+			Color:          s.Color(),
+			Size:           float32(s.Size()),
+		})
+	}
+	return stars
+}
+
+// randomStars gera estrelas cintilantes aleatórias numa casca esférica distante.
+func randomStars(rng *rand.Rand, count int) []Star {
+	stars := make([]Star, count)
+	for i := 0; i < count; i++ {
+		r := 600 + rng.Float64()*200
+		theta := rng.Float64() * 2 * math.Pi
+		phi := rng.Float64() * math.Pi
+		x := r * math.Sin(phi) * math.Cos(theta)
+		y := r * math.Cos(phi)
+		z := r * math.Sin(phi) * math.Sin(theta)
+		stars[i] = Star{
+			Position:       rl.NewVector3(float32(x), float32(y), float32(z)),
+			Phase:          rng.Float64() * 2 * math.Pi,
+			Speed:          0.005 + rng.Float64()*0.005,
+			BaseBrightness: 100 + rng.Intn(155),
+			Color:          rl.White,
+			Size:           1,
+			Twinkle:        true,
+		}
+	}
+	return stars
+}
+
+// eclipticToScene converte um vetor da eclíptica para a cena. O plano orbital é o
+// XZ do raylib e o polo norte eclíptico aponta para -Y, de modo que os ângulos
+// crescentes da simulação correspondem ao movimento direto dos planetas.
+func eclipticToScene(v astro.Vec3) rl.Vector3 {
+	return rl.NewVector3(float32(v.X), float32(-v.Z), float32(v.Y))
+}
+
 func (sim *Simulation) Update() {
 	sim.Time += 1.0 / 60.0
 
@@ -252,13 +317,10 @@ func (sim *Simulation) Update() {
 	// Atualiza posição e rastro do cometa
 	sim.Comet.Position.X += float32(sim.Comet.Speed * math.Cos(sim.Comet.Angle))
 	sim.Comet.Position.Z += float32(sim.Comet.Speed * math.Sin(sim.Comet.Angle))
-	sim.Comet.TailPoints = append([]rl.Vector3{sim.Comet.Position}, sim.Comet.TailPoints...)
-	if len(sim.Comet.TailPoints) > sim.Comet.TailMaxLength {
-		sim.Comet.TailPoints = sim.Comet.TailPoints[:sim.Comet.TailMaxLength]
-	}
+	sim.Comet.Tail.Add(sim.Comet.Position, sim.Time)
 	if sim.Comet.Position.X > 800 || sim.Comet.Position.Z > 800 {
 		sim.Comet.Position = rl.NewVector3(-50, 0, -50)
-		sim.Comet.TailPoints = sim.Comet.TailPoints[:0]
+		sim.Comet.Tail.Reset()
 	}
 
 	// Atualiza ângulos dos planetas e de suas luas
@@ -268,9 +330,6 @@ func (sim *Simulation) Update() {
 			m.Angle += m.OrbitSpeed
 		}
 	}
-
-	// Atualiza o ângulo da câmera para movimento orbital suave
-	sim.CamAngle += 0.001
 }
 
 // Desenha as órbitas dos planetas (no plano XZ)
@@ -296,22 +355,74 @@ void main() {
     gl_Position = mvp * vec4(vertexPosition, 1.0);
 }`
 
+// O fragment shader calcula as sombras analiticamente: cada planeta e lua é
+// uma esfera que cobre parte do disco do Sol visto do fragmento (o que dá
+// umbra, penumbra e eclipses anulares), e o anel de Saturno é um disco
+// vazado que corta a luz que o atravessa.
 const fragmentShaderSource = `#version 330
+#define MAX_OCCLUDERS 16
 in vec3 fragPos;
 in vec3 normal;
 uniform vec3 lightPos;      // Posição do Sol
+uniform float lightRadius;  // Raio do Sol (para a penumbra)
 uniform vec3 lightColor;
 uniform vec3 ambient;
 uniform vec3 viewPos;
 uniform float shininess;
 uniform vec3 objectColor;
+uniform float emissive;     // 1 para corpos que emitem luz própria
+uniform vec4 occluders[MAX_OCCLUDERS]; // centro (xyz) e raio (w)
+uniform float occluderCount;
+uniform vec3 ringCenter;
+uniform vec3 ringNormal;
+uniform vec2 ringRadii;     // raios interno e externo
+uniform float ringOpacity;
 out vec4 finalColor;
+
+// Fração do disco do Sol (raio angular a) coberta por um disco de raio
+// angular b cujo centro está a d radianos do centro do Sol.
+float diskOverlap(float a, float b, float d) {
+    if (d >= a + b) return 0.0;
+    float full = min(b * b / (a * a), 1.0);
+    return full * (1.0 - smoothstep(abs(a - b), a + b, d));
+}
+
+// Fração da luz do Sol que chega ao fragmento.
+float sunlight(vec3 toLight, float lightDist) {
+    float light = 1.0;
+    float sunSize = asin(min(lightRadius / lightDist, 1.0));
+    for (int i = 0; i < MAX_OCCLUDERS; i++) {
+        if (float(i) >= occluderCount) break;
+        vec3 toOcc = occluders[i].xyz - fragPos;
+        float dist = length(toOcc);
+        float r = occluders[i].w;
+        // O próprio corpo do fragmento e corpos atrás dele não fazem sombra
+        if (dist <= r * 1.01 || dist >= lightDist || dot(toOcc, toLight) <= 0.0) continue;
+        float sep = acos(clamp(dot(toOcc / dist, toLight), -1.0, 1.0));
+        light *= 1.0 - diskOverlap(sunSize, asin(min(r / dist, 1.0)), sep);
+    }
+    float denom = dot(toLight, ringNormal);
+    if (ringRadii.y > 0.0 && abs(denom) > 1e-4) {
+        float t = dot(ringCenter - fragPos, ringNormal) / denom;
+        if (t > 0.01 && t < lightDist) {
+            float rr = length(fragPos + toLight * t - ringCenter);
+            if (rr >= ringRadii.x && rr <= ringRadii.y) light *= 1.0 - ringOpacity;
+        }
+    }
+    return light;
+}
+
 void main() {
+    if (emissive > 0.5) {
+        finalColor = vec4(objectColor, 1.0);
+        return;
+    }
     // Componente ambiente
     vec3 ambientComponent = ambient * objectColor;
     // Luz difusa
     vec3 norm = normalize(normal);
-    vec3 lightDir = normalize(lightPos - fragPos);
+    float distance = length(lightPos - fragPos);
+    vec3 lightDir = (lightPos - fragPos) / distance;
     float diff = max(dot(norm, lightDir), 0.0);
     vec3 diffuse = diff * lightColor * objectColor;
     // Componente especular
@@ -319,22 +430,120 @@ void main() {
     vec3 reflectDir = reflect(-lightDir, norm);
     float spec = pow(max(dot(viewDir, reflectDir), 0.0), shininess);
     vec3 specular = spec * lightColor;
+    // Sombras: só a luz direta do Sol é bloqueada
+    float shadow = diff > 0.0 ? sunlight(lightDir, distance) : 0.0;
     // Atenuação (queda de intensidade com a distância)
-    float distance = length(lightPos - fragPos);
     float attenuation = 1.0 / (distance * distance * 0.0005 + 1.0);
-    vec3 result = (ambientComponent + diffuse + specular) * attenuation;
+    vec3 result = (ambientComponent + (diffuse + specular) * shadow) * attenuation;
     finalColor = vec4(result, 1.0);
 }`
 
+// maxOccluders deve ser igual a MAX_OCCLUDERS no fragment shader.
+const maxOccluders = 16
+
+// Geometria do anel de Saturno: inclinação em torno do eixo X (graus), raios
+// do mesh em múltiplos da escala do modelo e opacidade para a sombra.
+const (
+	ringTilt    = 25
+	ringInner   = 1.5
+	ringOuter   = 2.0
+	ringScale   = 3
+	ringOpacity = 0.6
+)
+
+// Position devolve a posição do planeta na cena.
+func (p *Planet) Position() rl.Vector3 {
+	return rl.NewVector3(float32(p.OrbitRadius*math.Cos(p.Angle)), 0, float32(p.OrbitRadius*math.Sin(p.Angle)))
+}
+
+// Position devolve a posição do asteroide na cena.
+func (a *Asteroid) Position() rl.Vector3 {
+	return rl.NewVector3(float32(a.OrbitRadius*math.Cos(a.Angle)), 0, float32(a.OrbitRadius*math.Sin(a.Angle)))
+}
+
+// Position devolve a posição da lua em torno do planeta em planetPos.
+func (m *Moon) Position(planetPos rl.Vector3) rl.Vector3 {
+	return rl.NewVector3(
+		planetPos.X+float32(m.OrbitRadius*math.Cos(m.Angle)),
+		planetPos.Y,
+		planetPos.Z+float32(m.OrbitRadius*math.Sin(m.Angle)),
+	)
+}
+
+// occluder é um corpo que faz sombra: centro e raio na cena.
+type occluder struct {
+	Position rl.Vector3
+	Radius   float32
+}
+
+// setShadowUniforms envia ao shader os corpos que fazem sombra (planetas,
+// luas, asteroides e o cometa) e a geometria do anel de Saturno. O shader
+// aceita só maxOccluders; os corpos são ordenados pelo tamanho aparente visto
+// da câmera e os menores ficam sem sombra, o que é avisado uma vez no log.
+func (sim *Simulation) setShadowUniforms(shader rl.Shader, camera rl.Camera3D) {
+	var bodies []occluder
+	ring := []float32{0, 0}
+	for _, p := range sim.Planets {
+		pos := p.Position()
+		bodies = append(bodies, occluder{pos, p.Radius})
+		for _, m := range p.Moons {
+			bodies = append(bodies, occluder{m.Position(pos), m.Radius})
+		}
+		if p.Name == "Saturn" {
+			tilt := float64(ringTilt) * math.Pi / 180
+			normal := []float32{0, float32(math.Cos(tilt)), float32(math.Sin(tilt))}
+			rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "ringCenter"), []float32{pos.X, pos.Y, pos.Z}, rl.ShaderUniformVec3)
+			rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "ringNormal"), normal, rl.ShaderUniformVec3)
+			ring = []float32{ringInner * ringScale * p.Radius, ringOuter * ringScale * p.Radius}
+		}
+	}
+	for _, a := range sim.Asteroids {
+		bodies = append(bodies, occluder{a.Position(), a.Radius})
+	}
+	bodies = append(bodies, occluder{sim.Comet.Position, cometRadius})
+
+	if len(bodies) > maxOccluders {
+		apparent := func(o occluder) float32 {
+			return o.Radius / max(rl.Vector3Distance(o.Position, camera.Position), 1)
+		}
+		sort.Slice(bodies, func(i, j int) bool { return apparent(bodies[i]) > apparent(bodies[j]) })
+		if !sim.occluderCapLogged {
+			log.Printf("sombras: %d corpos, o shader aceita %d; os menores vistos da câmera ficam sem sombra", len(bodies), maxOccluders)
+			sim.occluderCapLogged = true
+		}
+		bodies = bodies[:maxOccluders]
+	}
+	occluders := make([]float32, 0, 4*len(bodies))
+	for _, o := range bodies {
+		occluders = append(occluders, o.Position.X, o.Position.Y, o.Position.Z, o.Radius)
+	}
+
+	rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "ringRadii"), ring, rl.ShaderUniformVec2)
+	rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "ringOpacity"), []float32{ringOpacity}, rl.ShaderUniformFloat)
+	rl.SetShaderValueV(shader, rl.GetShaderLocation(shader, "occluders"), occluders, rl.ShaderUniformVec4, int32(len(occluders)/4))
+	rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "occluderCount"), []float32{float32(len(occluders) / 4)}, rl.ShaderUniformFloat)
+}
+
 // ─────────────────────────────────────────────
 // Função para desenhar uma esfera com o shader customizado
 func drawLitSphere(model rl.Model, shader rl.Shader, pos rl.Vector3, radius float32, col rl.Color) {
+	drawSphereWith(model, shader, pos, radius, col, 0)
+}
+
+// drawEmissiveSphere desenha uma esfera com a própria cor, sem iluminação nem
+// sombra (o Sol).
+func drawEmissiveSphere(model rl.Model, shader rl.Shader, pos rl.Vector3, radius float32, col rl.Color) {
+	drawSphereWith(model, shader, pos, radius, col, 1)
+}
+
+func drawSphereWith(model rl.Model, shader rl.Shader, pos rl.Vector3, radius float32, col rl.Color, emissive float32) {
 	objColor := []float32{
 		float32(col.R) / 255.0,
 		float32(col.G) / 255.0,
 		float32(col.B) / 255.0,
 	}
 	rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "objectColor"), objColor, rl.ShaderUniformVec3)
+	rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "emissive"), []float32{emissive}, rl.ShaderUniformFloat)
 	rl.DrawModelEx(model, pos, rl.NewVector3(0, 1, 0), 0, rl.NewVector3(radius, radius, radius), rl.White)
 }
 
@@ -417,7 +626,8 @@ func (sim *Simulation) Draw3D(sphereModel, ringModel rl.Model, shader rl.Shader,
 	// Atualiza os uniforms do shader
 	lightPos := []float32{0.0, 0.0, 0.0}
 	lightColor := []float32{1.0, 1.0, 1.0}
-	ambient := []float32{0.7, 0.7, 0.7}
+	// Ambiente baixo o bastante para que as sombras e os lados noturnos apareçam
+	ambient := []float32{0.25, 0.25, 0.25}
 	shininess := []float32{32.0}
 
 	rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "lightPos"), lightPos, rl.ShaderUniformVec3)
@@ -426,45 +636,40 @@ func (sim *Simulation) Draw3D(sphereModel, ringModel rl.Model, shader rl.Shader,
 	rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "shininess"), shininess, rl.ShaderUniformFloat)
 	rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "viewPos"),
 		[]float32{camera.Position.X, camera.Position.Y, camera.Position.Z}, rl.ShaderUniformVec3)
+	rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "lightRadius"), []float32{sim.SunRadius}, rl.ShaderUniformFloat)
+	sim.setShadowUniforms(shader, camera)
 
 	// Desenha o Sol
-	drawLitSphere(sphereModel, shader, rl.NewVector3(0, 0, 0), sim.SunRadius, rl.Yellow)
+	drawEmissiveSphere(sphereModel, shader, rl.NewVector3(0, 0, 0), sim.SunRadius, rl.Yellow)
 
 	// Desenha as órbitas dos planetas
 	sim.DrawOrbitPaths()
 
 	// Desenha os planetas e suas luas
 	for _, p := range sim.Planets {
-		x := float32(p.OrbitRadius * math.Cos(p.Angle))
-		z := float32(p.OrbitRadius * math.Sin(p.Angle))
-		planetPos := rl.NewVector3(x, 0, z)
+		planetPos := p.Position()
 		drawLitSphere(sphereModel, shader, planetPos, p.Radius, p.Color)
 		// Se for Saturn, desenha os anéis
 		if p.Name == "Saturn" {
-			rl.DrawModelEx(ringModel, planetPos, rl.NewVector3(1, 0, 0), 25, rl.NewVector3(p.Radius*3, 1, p.Radius*3), rl.LightGray)
+			rl.SetShaderValue(shader, rl.GetShaderLocation(shader, "objectColor"), []float32{0.78, 0.78, 0.78}, rl.ShaderUniformVec3)
+			rl.DrawModelEx(ringModel, planetPos, rl.NewVector3(1, 0, 0), ringTilt, rl.NewVector3(p.Radius*ringScale, 1, p.Radius*ringScale), rl.LightGray)
 		}
 		for _, m := range p.Moons {
-			mx := planetPos.X + float32(m.OrbitRadius*math.Cos(m.Angle))
-			mz := planetPos.Z + float32(m.OrbitRadius*math.Sin(m.Angle))
-			moonPos := rl.NewVector3(mx, 0, mz)
-			drawLitSphere(sphereModel, shader, moonPos, m.Radius, m.Color)
+			drawLitSphere(sphereModel, shader, m.Position(planetPos), m.Radius, m.Color)
 		}
 	}
 	// Desenha os asteroides
 	for _, a := range sim.Asteroids {
-		ax := float32(a.OrbitRadius * math.Cos(a.Angle))
-		az := float32(a.OrbitRadius * math.Sin(a.Angle))
-		asteroidPos := rl.NewVector3(ax, 0, az)
-		drawLitSphere(sphereModel, shader, asteroidPos, a.Radius, rl.Gray)
+		drawLitSphere(sphereModel, shader, a.Position(), a.Radius, rl.Gray)
 	}
 	// Desenha o rastro do cometa
-	for i := 0; i < len(sim.Comet.TailPoints)-1; i++ {
-		alpha := uint8(200 * (1 - float32(i)/float32(len(sim.Comet.TailPoints))))
-		col := rl.NewColor(255, 255, 255, alpha)
-		drawLitSphere(sphereModel, shader, sim.Comet.TailPoints[i], 2, col)
-	}
+	sim.Comet.Tail.Segments(sim.Time, func(a, b rl.Vector3, age float64) {
+		col := rl.NewColor(255, 255, 255, uint8(200*(1-age)))
+		drawLitSphere(sphereModel, shader, a, 2, col)
+		rl.DrawLine3D(a, b, col)
+	})
 	// Desenha o cometa
-	drawLitSphere(sphereModel, shader, sim.Comet.Position, 4, rl.White)
+	drawLitSphere(sphereModel, shader, sim.Comet.Position, cometRadius, rl.White)
 	// Desenha as estrelas cintilantes
 	for _, star := range sim.Stars {
 		brightness := float32(128 + 127*math.Sin(star.Phase))
@@ -473,14 +678,104 @@ func (sim *Simulation) Draw3D(sphereModel, ringModel rl.Model, shader rl.Shader,
 		} else if brightness > 255 {
 			brightness = 255
 		}
-		col := rl.NewColor(255, 255, 255, uint8(brightness))
-		drawLitSphere(sphereModel, shader, star.Position, 1, col)
+		col := star.Color
+		if star.Twinkle {
+			col.A = uint8(brightness)
+		}
+		drawLitSphere(sphereModel, shader, star.Position, star.Size, col)
 	}
 
 	// OBS.: A função de skybox foi removida para evitar erros (rl.DrawSkybox não está disponível nesta versão).
 }
 
+// ─────────────────────────────────────────────
+// Caminho da câmera por keyframes
+
+// Caminho padrão, sem arquivo: uma órbita em torno do Sol com raio e altura
+// da câmera inicial e uma volta a cada 0,001 rad por frame.
+const (
+	orbitCameraRadius = 600
+	orbitCameraHeight = 300
+	orbitCameraPeriod = 2 * math.Pi / 0.001 / 60
+	orbitCameraKeys   = 24
+)
+
+// defaultCameraFile é onde o caminho editado é gravado quando não há -camera.
+const defaultCameraFile = "camera.txt"
+
+func vecToScene(v astro.Vec3) rl.Vector3 {
+	return rl.NewVector3(float32(v.X), float32(v.Y), float32(v.Z))
+}
+
+func sceneToVec(v rl.Vector3) astro.Vec3 {
+	return astro.Vec3{X: float64(v.X), Y: float64(v.Y), Z: float64(v.Z)}
+}
+
+// applyKeyframe coloca a câmera na pose do keyframe.
+func applyKeyframe(camera *rl.Camera3D, k campath.Keyframe) {
+	camera.Position = vecToScene(k.Position)
+	camera.Target = vecToScene(k.Target)
+	camera.Fovy = float32(k.Fovy)
+}
+
+// cameraKeyframe devolve a pose atual da câmera como keyframe do instante t.
+func cameraKeyframe(camera rl.Camera3D, t float64) campath.Keyframe {
+	return campath.Keyframe{Time: t, Position: sceneToVec(camera.Position), Target: sceneToVec(camera.Target), Fovy: float64(camera.Fovy)}
+}
+
+// saveCameraPath grava o caminho no arquivo, no formato de campath.Load.
+func saveCameraPath(path campath.Path, file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err := path.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// drawCameraPath desenha, no modo de edição, a curva percorrida pela câmera
+// e a posição de cada keyframe.
+func drawCameraPath(path campath.Path) {
+	if len(path.Keys) == 0 {
+		return
+	}
+	const samples = 300
+	start, end := path.Keys[0].Time, path.Keys[len(path.Keys)-1].Time
+	if path.Period > 0 {
+		end = start + path.Period
+	}
+	prev := vecToScene(path.At(start).Position)
+	for i := 1; i <= samples; i++ {
+		next := vecToScene(path.At(start + (end-start)*float64(i)/samples).Position)
+		rl.DrawLine3D(prev, next, rl.NewColor(255, 200, 80, 200))
+		prev = next
+	}
+	for _, k := range path.Keys {
+		rl.DrawSphereWires(vecToScene(k.Position), 3, 4, 8, rl.Orange)
+		rl.DrawLine3D(vecToScene(k.Position), vecToScene(k.Target), rl.NewColor(255, 200, 80, 80))
+	}
+}
+
 func main() {
+	cameraFile := flag.String("camera", "", "arquivo de keyframes da câmera (vazio: órbita automática)")
+	format := flag.String("formato", "avi", "formato da gravação da tecla F9: png, gif ou avi")
+	fps := flag.Float64("fps", 30, "quadros por segundo simulado da gravação")
+	seed := flag.Int64("semente", 0, "semente das partes aleatórias da cena (0: a hora atual)")
+	resolution := flag.String("resolucao", "", "resolução dos pôsteres da tecla F10, como 7680x4320 (vazio: a da janela)")
+	poster := flag.String("poster", "", "salva um pôster da cena inicial neste PNG e sai")
+	flag.Parse()
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	recordFormat, err := recorder.ParseFormat(*format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var rec *recorder.Recorder // gravação em andamento (tecla F9)
+
 	// Configurações da janela e MSAA
 	screenWidth := int32(1280)
 	screenHeight := int32(720)
@@ -509,7 +804,7 @@ func main() {
 	defer rl.UnloadModel(sphereModel)
 
 	// Modelo para o anel de Saturno
-	ringMesh := generateRingMesh(1.5, 2.0, 100)
+	ringMesh := generateRingMesh(ringInner, ringOuter, 100)
 	ringModel := rl.LoadModelFromMesh(ringMesh)
 	ringModel.Materials.Shader = shader
 	defer rl.UnloadModel(ringModel)
@@ -517,16 +812,102 @@ func main() {
 	// OBS.: O código para carregar o cubemap (skybox) foi removido,
 	// pois as funções rl.LoadTextureCubemap e rl.DrawSkybox não estão definidas na sua versão.
 
-	sim := NewSimulation()
+	sim := NewSimulation(*seed)
+	posterWidth, posterHeight := int(screenWidth), int(screenHeight)
+	if *resolution != "" {
+		if posterWidth, posterHeight, err = recorder.ParseResolution(*resolution); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Caminho da câmera: lido de -camera ou a órbita automática padrão, que
+	// é trocada por um caminho vazio no primeiro keyframe gravado
+	path := campath.Orbit(orbitCameraRadius, orbitCameraHeight, orbitCameraPeriod, 45, orbitCameraKeys)
+	defaultPath := *cameraFile == ""
+	if !defaultPath {
+		if path, err = campath.Load(*cameraFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	saveFile := *cameraFile
+	if saveFile == "" {
+		saveFile = defaultCameraFile
+	}
+	editing := false
+	status := ""
 
 	// Loop principal
 	for !rl.WindowShouldClose() {
-		// Atualiza a simulação e a câmera (movimento orbital suave)
+		// Tab alterna entre reproduzir o caminho e editá-lo com a câmera livre:
+		// K grava um keyframe no instante atual, Backspace apaga o caminho e
+		// Enter o salva. R reinicia a simulação e o relógio.
+		if rl.IsKeyPressed(rl.KeyTab) {
+			editing = !editing
+		}
+		if rl.IsKeyPressed(rl.KeyR) {
+			sim = NewSimulation(*seed)
+		}
+		if rl.IsKeyPressed(rl.KeyF9) {
+			rec = rlcapture.ToggleRecording(rec, recordFormat, *fps)
+		}
+		if editing {
+			if rl.IsKeyPressed(rl.KeyK) {
+				if defaultPath {
+					path, defaultPath = campath.Path{}, false
+				}
+				path.Add(cameraKeyframe(camera, sim.Time))
+				// Um keyframe depois do fim do loop desfaz o loop
+				if path.Validate() != nil {
+					path.Period = 0
+					status = "O keyframe passa do período: o caminho deixou de repetir"
+				}
+			}
+			if rl.IsKeyPressed(rl.KeyBackspace) {
+				path, defaultPath = campath.Path{}, false
+			}
+			if rl.IsKeyPressed(rl.KeyEnter) {
+				if err := saveCameraPath(path, saveFile); err != nil {
+					status = err.Error()
+				} else {
+					status = "Caminho salvo em " + saveFile
+				}
+			}
+		}
+
+		// Atualiza a simulação e a câmera, que segue o caminho no relógio da
+		// simulação ou, na edição, os controles da câmera livre
 		sim.Update()
-		camRadius := float32(600)
-		camera.Position.X = camRadius * float32(math.Cos(sim.CamAngle))
-		camera.Position.Z = camRadius * float32(math.Sin(sim.CamAngle))
-		camera.Target = rl.NewVector3(0, 0, 0)
+		if editing {
+			rl.UpdateCamera(&camera, rl.CameraFree)
+		} else if len(path.Keys) > 0 {
+			applyKeyframe(&camera, path.At(sim.Time))
+		}
+
+		// Pôster: F10 (ou -poster, que sai em seguida) desenha a cena atual,
+		// sem textos nem o caminho, na resolução de -resolucao
+		if rl.IsKeyPressed(rl.KeyF10) || *poster != "" {
+			name := *poster
+			if name == "" {
+				name = recorder.PosterName(time.Now())
+			}
+			img := rlcapture.RenderPoster(posterWidth, posterHeight, camera, rl.Black, nil, func() {
+				sim.Draw3D(sphereModel, ringModel, shader, camera)
+			})
+			meta := []recorder.Metadata{
+				{Key: "Software", Value: "Simulação 3D Realista do Sistema Solar"},
+				{Key: "Simulation Time", Value: fmt.Sprintf("%.3f s", sim.Time)},
+				rlcapture.CameraMetadata(camera),
+				{Key: "Seed", Value: fmt.Sprint(sim.Seed)},
+			}
+			if err := recorder.WritePNGMetadata(name, img, meta); err != nil {
+				log.Print(err)
+			} else {
+				log.Printf("pôster salvo em %s (%dx%d)", name, posterWidth, posterHeight)
+			}
+			if *poster != "" {
+				return
+			}
+		}
 
 		rl.BeginDrawing()
 		rl.ClearBackground(rl.Black)
@@ -534,9 +915,25 @@ func main() {
 		rl.BeginMode3D(camera)
 		// Desenha a cena (sem skybox)
 		sim.Draw3D(sphereModel, ringModel, shader, camera)
+		if editing {
+			drawCameraPath(path)
+		}
 		rl.EndMode3D()
 
 		rl.DrawText("Simulação 3D Realista do Sistema Solar", 10, 10, 20, rl.White)
+		if editing {
+			rl.DrawText(fmt.Sprintf("Edição do caminho | t = %.2f s | %d keyframes", sim.Time, len(path.Keys)), 10, 40, 20, rl.White)
+			rl.DrawText("WASD e mouse: câmera | K: Keyframe | Backspace: Apagar | Enter: Salvar | R: Reiniciar | Tab: Reproduzir", 10, 70, 20, rl.White)
+			rl.DrawText(status, 10, 100, 20, rl.White)
+		} else {
+			rl.DrawText("Tab: Editar o caminho da câmera | R: Reiniciar | F9: Gravar | F10: Pôster", 10, 40, 20, rl.White)
+		}
+		rec = rlcapture.RecordFrame(rec, screenWidth)
 		rl.EndDrawing()
 	}
+
+	// Fecha a gravação que ficou aberta ao sair
+	if rec != nil {
+		rlcapture.ToggleRecording(rec, recordFormat, *fps)
+	}
 }
@@ -1,16 +1,40 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"image"
+	"log"
 	"math"
 	"math/rand"
+	"os"
+	"sort"
 	"time"
 
+	"go-playground/astro"
+	"go-playground/catalog"
+	"go-playground/recorder"
+	"go-playground/recorder/rlcapture"
+	"go-playground/trail"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 // Definição de uma cor Silver (já que rl.Silver não está definida)
 var Silver = rl.NewColor(192, 192, 192, 255)
 
+// Arquivos do catálogo de estrelas (formato BSC5) e das figuras das constelações,
+// e raio da esfera celeste da cena.
+const (
+	starCatalogFile       = "data/bsc5.dat"
+	constellationFile     = "data/constellations.txt"
+	celestialSphereRadius = 700
+)
+
+// orbitalDaysPerFrame é o passo do relógio da simulação nos modos orbitais:
+// a Terra da simulação (0,02 rad/frame) completa um ano a cada 2π/0,02 frames.
+const orbitalDaysPerFrame = 365.25 * 0.02 / (2 * math.Pi)
+
 // Definindo constantes para os modos de câmera (para os modos "normais")
 const (
 	CameraFree    = 1 // Modo livre (free)
@@ -24,6 +48,16 @@ type Star struct {
 	Phase          float64
 	Speed          float64
 	BaseBrightness int
+	Color          rl.Color
+	Size           float32
+	Twinkle        bool // apenas as estrelas aleatórias (sem catálogo) cintilam
+}
+
+// ConstellationFigure é uma constelação já posicionada na esfera celeste da cena.
+type ConstellationFigure struct {
+	Name     string
+	Segments [][2]rl.Vector3
+	Label    rl.Vector3
 }
 
 type Moon struct {
@@ -32,6 +66,15 @@ type Moon struct {
 	OrbitSpeed  float64
 	Radius      float32
 	Color       rl.Color
+	Mass        float64 // massas terrestres
+	ShowSpheres bool    // esferas de influência e de Hill (tecla I)
+
+	// Plano da órbita: inclinação sobre a eclíptica, longitude do nodo
+	// ascendente e sua taxa de precessão (rad/dia; negativa se regride).
+	// Angle é medido a partir do eixo x ao longo do plano, passando pelo nodo.
+	Inclination float64
+	Node        float64
+	NodeRate    float64
 }
 
 type Planet struct {
@@ -41,6 +84,8 @@ type Planet struct {
 	Angle       float64
 	OrbitSpeed  float64
 	Color       rl.Color
+	Mass        float64 // massas terrestres
+	ShowSpheres bool    // esferas de influência e de Hill (tecla I)
 	Moons       []*Moon
 }
 
@@ -51,12 +96,52 @@ type Asteroid struct {
 	Radius      float32
 }
 
+// cometTailSeconds é a duração do rastro do cometa (20 frames).
+const cometTailSeconds = 20.0 / 60
+
 type Comet struct {
-	Position      rl.Vector3
-	Angle         float64
-	Speed         float64
-	TailPoints    []rl.Vector3
-	TailMaxLength int
+	Position rl.Vector3
+	Angle    float64
+	Speed    float64
+	Tail     *trail.Trail[rl.Vector3] // na cena heliocêntrica, em segundos de Time
+}
+
+// Trojan é um asteroide movido pela gravidade do Sol e de um planeta (problema
+// restrito de três corpos), usado para mostrar órbitas de girino e de ferradura
+// em torno dos pontos L4 e L5. Unidades da cena: pixels e frames.
+type Trojan struct {
+	Position astro.Vec3               // coordenadas eclípticas da cena
+	Velocity astro.Vec3               // por frame
+	Path     *trail.Trail[rl.Vector3] // no referencial girante, em segundos de Time
+}
+
+// ManeuverNode é uma queima impulsiva agendada: Δv (pixels/frame) nas direções
+// progrado, normal e radial da órbita no instante Time (em frames simulados).
+type ManeuverNode struct {
+	Time     float64
+	Prograde float64
+	Normal   float64
+	Radial   float64
+}
+
+// DeltaV é o módulo do impulso do nó.
+func (n ManeuverNode) DeltaV() float64 {
+	return math.Sqrt(n.Prograde*n.Prograde + n.Normal*n.Normal + n.Radial*n.Radial)
+}
+
+// Spacecraft é uma nave movida pela gravidade, com propelente limitado e uma
+// lista de nós de manobra ordenada pelo tempo. Unidades da cena: pixels e frames.
+type Spacecraft struct {
+	Name         string
+	Position     astro.Vec3 // coordenadas eclípticas da cena
+	Velocity     astro.Vec3 // por frame
+	DryMass      float64    // kg
+	Propellant   float64    // kg
+	ExhaustSpeed float64    // pixels/frame
+	Nodes        []ManeuverNode
+
+	// Trajetória prevista, um trecho antes do primeiro nó e um depois de cada nó
+	Prediction [][]rl.Vector3
 }
 
 type Simulation struct {
@@ -66,6 +151,82 @@ type Simulation struct {
 	Asteroids []Asteroid // Inclui cinturão principal e o Kuiper Belt
 	Comet     Comet
 	Time      float64
+	Ticks     float64    // frames simulados desde o início
+	Seed      int64      // semente dos asteroides, das estrelas e do cometa
+	rng       *rand.Rand // gerador criado com Seed (rand.Seed não fixa mais a sequência global)
+
+	// Relógio astronômico: data juliana (UTC) e dias avançados por frame
+	JD        float64
+	TimeScale float64
+
+	// Referencial em que a cena é desenhada (alternado com a tecla F) e a
+	// posição da sua origem na cena, recalculada a cada frame
+	Frame       astro.Frame
+	frameOrigin rl.Vector3
+
+	// Rastros das órbitas (tecla 4), guardados já no referencial da cena
+	// para mostrar os caminhos vistos nele, como os epiciclos no geocêntrico;
+	// a duração é em dias de JD
+	ShowTrails bool
+	TrailDays  float64
+	Trails     map[PickedBody]*trail.Trail[rl.Vector3]
+
+	// Referencial girante travado em um planeta (tecla R) e população de
+	// troianos integrada pela gravidade (tecla T)
+	RotatingPlanet *Planet
+	TrojanHost     *Planet
+	Trojans        []Trojan
+
+	// Nave com nós de manobra (tecla V) e nó selecionado para edição; o
+	// sobrevoo registra a última passagem da nave por um planeta
+	Craft         *Spacecraft
+	Flyby         *Flyby
+	FlybyTarget   *Planet
+	SelectedNode  int
+	NodeComponent int // 0 progrado, 1 normal, 2 radial
+
+	// Corpo cujos elementos orbitais osculantes aparecem no painel (tecla X)
+	Selected string
+
+	// Corpo escolhido com o mouse, destacado na cena e descrito no painel
+	// de detalhes; o duplo clique leva a câmera até ele
+	Picked    PickedBody
+	lastClick float64 // rl.GetTime do último clique na cena
+
+	// Quem está dentro de cada esfera de influência ou de Hill, e os avisos
+	// de entrada e saída das luas e do cometa
+	insideSphere  map[string]bool
+	Notifications []Notification
+
+	// Com Ephemeris, os planetas seguem as longitudes heliocêntricas reais na
+	// data JD em vez das velocidades da simulação (tecla J)
+	Ephemeris bool
+
+	// Porkchop carregado com -porkchop (painel na tecla K) e a sonda da
+	// transferência escolhida nele
+	Porkchop      [][]astro.PorkchopCell
+	ShowPorkchop  bool
+	TransferProbe *TransferProbe
+
+	// Tabela de eventos aberta (eclipses na tecla Z, fenômenos na tecla U);
+	// nil quando fechada
+	Events *EventTable
+
+	// Planejador de transferências entre planetas (tecla H); nil quando desligado
+	Transfer *TransferPlan
+
+	// Par de corpos cujos pontos de Lagrange são marcados (tecla L):
+	// 0 desliga, i > 0 escolhe lagrangePairs[i-1]
+	LagrangePair int
+
+	// Catálogo de estrelas e figuras das constelações (alternadas com a tecla C).
+	// SkySegments são os traços das figuras como direções equatoriais J2000,
+	// montados uma vez para o planetário; SkyStatus explica o que faltou.
+	SkyCatalog         []catalog.Star
+	SkySegments        [][2]astro.Vec3
+	Constellations     []ConstellationFigure
+	ShowConstellations bool
+	SkyStatus          string
 
 	// Campos para o efeito de explosão (impacto)
 	ExplosionActive   bool
@@ -74,12 +235,18 @@ type Simulation struct {
 	ExplosionPosition rl.Vector3
 }
 
-// NewSimulation cria e inicializa os corpos celestes
-func NewSimulation() *Simulation {
+// NewSimulation cria e inicializa os corpos celestes; a semente fixa as
+// partes aleatórias da cena.
+func NewSimulation(seed int64) *Simulation {
 	sim := &Simulation{
+		rng:               rand.New(rand.NewSource(seed)),
+		Seed:              seed,
 		SunRadius:         40,
 		Planets:           make([]*Planet, 0),
 		Time:              0,
+		JD:                astro.JulianDate(time.Now()),
+		TimeScale:         orbitalDaysPerFrame,
+		Frame:             astro.HeliocentricFrame,
 		ExplosionActive:   false,
 		ExplosionDuration: 1.0, // duração da explosão em segundos
 	}
@@ -92,6 +259,7 @@ func NewSimulation() *Simulation {
 		Angle:       0,
 		OrbitSpeed:  0.04,
 		Color:       rl.Gray,
+		Mass:        0.0553,
 	})
 	sim.Planets = append(sim.Planets, &Planet{
 		Name:        "Venus",
@@ -100,6 +268,7 @@ func NewSimulation() *Simulation {
 		Angle:       0,
 		OrbitSpeed:  0.03,
 		Color:       rl.Orange,
+		Mass:        0.815,
 	})
 	terra := &Planet{
 		Name:        "Terra",
@@ -108,6 +277,7 @@ func NewSimulation() *Simulation {
 		Angle:       0,
 		OrbitSpeed:  0.02,
 		Color:       rl.Blue,
+		Mass:        1,
 	}
 	terra.Moons = []*Moon{
 		{
@@ -116,6 +286,10 @@ func NewSimulation() *Simulation {
 			OrbitSpeed:  0.05,
 			Radius:      3,
 			Color:       rl.LightGray,
+			Mass:        0.0123,
+			Inclination: astro.MoonInclination,
+			Node:        astro.MoonNode(sim.JD),
+			NodeRate:    -2 * math.Pi / 6798.38,
 		},
 	}
 	sim.Planets = append(sim.Planets, terra)
@@ -126,6 +300,7 @@ func NewSimulation() *Simulation {
 		Angle:       0,
 		OrbitSpeed:  0.015,
 		Color:       rl.Red,
+		Mass:        0.107,
 	})
 	// Jupiter com múltiplas luas
 	jupiter := &Planet{
@@ -135,6 +310,7 @@ func NewSimulation() *Simulation {
 		Angle:       0,
 		OrbitSpeed:  0.01,
 		Color:       rl.Brown,
+		Mass:        317.8,
 	}
 	jupiter.Moons = []*Moon{
 		{
@@ -143,6 +319,7 @@ func NewSimulation() *Simulation {
 			OrbitSpeed:  0.06,
 			Radius:      3,
 			Color:       rl.LightGray,
+			Mass:        0.0150,
 		},
 		{
 			OrbitRadius: 30,
@@ -150,6 +327,7 @@ func NewSimulation() *Simulation {
 			OrbitSpeed:  0.04,
 			Radius:      2,
 			Color:       Silver,
+			Mass:        0.0080,
 		},
 		{
 			OrbitRadius: 40,
@@ -157,6 +335,7 @@ func NewSimulation() *Simulation {
 			OrbitSpeed:  0.035,
 			Radius:      2,
 			Color:       rl.LightGray,
+			Mass:        0.0248,
 		},
 	}
 	sim.Planets = append(sim.Planets, jupiter)
@@ -167,6 +346,7 @@ func NewSimulation() *Simulation {
 		Angle:       0,
 		OrbitSpeed:  0.008,
 		Color:       rl.Gold,
+		Mass:        95.2,
 	})
 	sim.Planets = append(sim.Planets, &Planet{
 		Name:        "Urano",
@@ -175,6 +355,7 @@ func NewSimulation() *Simulation {
 		Angle:       0,
 		OrbitSpeed:  0.006,
 		Color:       rl.NewColor(173, 216, 230, 255),
+		Mass:        14.5,
 	})
 	sim.Planets = append(sim.Planets, &Planet{
 		Name:        "Netuno",
@@ -183,6 +364,7 @@ func NewSimulation() *Simulation {
 		Angle:       0,
 		OrbitSpeed:  0.005,
 		Color:       rl.DarkBlue,
+		Mass:        17.1,
 	})
 	sim.Planets = append(sim.Planets, &Planet{
 		Name:        "Plutao",
@@ -191,72 +373,153 @@ func NewSimulation() *Simulation {
 		Angle:       0,
 		OrbitSpeed:  0.004,
 		Color:       rl.Brown,
+		Mass:        0.0022,
 	})
 
-	// Estrelas distribuídas numa casca esférica distante
-	starCount := 200
-	sim.Stars = make([]Star, starCount)
-	for i := 0; i < starCount; i++ {
-		r := 600 + rand.Float64()*200
-		theta := rand.Float64() * 2 * math.Pi
-		phi := rand.Float64() * math.Pi
-		x := r * math.Sin(phi) * math.Cos(theta)
-		y := r * math.Cos(phi)
-		z := r * math.Sin(phi) * math.Sin(theta)
-		sim.Stars[i] = Star{
-			Position:       rl.NewVector3(float32(x), float32(y), float32(z)),
-			Phase:          rand.Float64() * 2 * math.Pi,
-			Speed:          0.005 + rand.Float64()*0.005,
-			BaseBrightness: 100 + rand.Intn(155),
+	// Estrelas do catálogo BSC na esfera celeste; sem o arquivo, volta ao
+	// campo aleatório numa casca esférica distante
+	if cat, err := catalog.LoadBSC(starCatalogFile); err == nil {
+		sim.Stars = catalogStars(cat)
+		sim.SkyCatalog = cat
+		if cons, err := catalog.LoadConstellations(constellationFile); err == nil {
+			sim.Constellations = constellationFigures(cat, cons)
+			sim.SkySegments = skySegments(cat, cons)
+		} else {
+			sim.SkyStatus = fmt.Sprintf("figuras das constelações indisponíveis: %v", err)
+			log.Print(sim.SkyStatus)
 		}
+	} else {
+		sim.SkyStatus = fmt.Sprintf("catálogo de estrelas indisponível (%v); usando estrelas aleatórias", err)
+		log.Print(sim.SkyStatus)
+		sim.Stars = randomStars(sim.rng, 200)
 	}
 
 	// Cinturão de Asteroides (principal e Kuiper Belt)
 	asteroidCount := 150
 	sim.Asteroids = make([]Asteroid, 0, asteroidCount+50)
 	for i := 0; i < asteroidCount; i++ {
-		orbitRadius := 210 + rand.Float64()*30
+		orbitRadius := 210 + sim.rng.Float64()*30
 		sim.Asteroids = append(sim.Asteroids, Asteroid{
 			OrbitRadius: orbitRadius,
-			Angle:       rand.Float64() * 2 * math.Pi,
-			OrbitSpeed:  0.008 + rand.Float64()*0.004,
-			Radius:      1 + float32(rand.Float64()*1.5),
+			Angle:       sim.rng.Float64() * 2 * math.Pi,
+			OrbitSpeed:  0.008 + sim.rng.Float64()*0.004,
+			Radius:      1 + float32(sim.rng.Float64()*1.5),
 		})
 	}
 	// Kuiper Belt
 	kuiperCount := 50
 	for i := 0; i < kuiperCount; i++ {
-		orbitRadius := 500 + rand.Float64()*100
+		orbitRadius := 500 + sim.rng.Float64()*100
 		sim.Asteroids = append(sim.Asteroids, Asteroid{
 			OrbitRadius: orbitRadius,
-			Angle:       rand.Float64() * 2 * math.Pi,
-			OrbitSpeed:  0.003 + rand.Float64()*0.002,
-			Radius:      0.5 + float32(rand.Float64()*1.0),
+			Angle:       sim.rng.Float64() * 2 * math.Pi,
+			OrbitSpeed:  0.003 + sim.rng.Float64()*0.002,
+			Radius:      0.5 + float32(sim.rng.Float64()*1.0),
 		})
 	}
 
 	// Inicializa o cometa com posição e direção aleatórias (na periferia)
+	sim.Comet.Tail = trail.New[rl.Vector3](cometTailSeconds)
 	resetComet(sim)
-	// Define o comprimento máximo do rastro do cometa
-	sim.Comet.TailMaxLength = 20
-
-	// Semente para números aleatórios
-	rand.Seed(time.Now().UnixNano())
 	return sim
 }
 
+// catalogStars posiciona as estrelas do catálogo na esfera celeste da cena,
+// com tamanho e cor derivados da magnitude e do índice B–V.
+func catalogStars(cat []catalog.Star) []Star {
+	stars := make([]Star, 0, len(cat))
+	for _, s := range cat {
+		stars = append(stars, Star{
+			Position:       celestialPoint(s.Direction()),
+			BaseBrightness: int(s.Brightness()),
+			Color:          s.Color(),
+			Size:           float32(s.Size()),
+		})
+	}
+	return stars
+}
+
+// constellationFigures converte as figuras do arquivo em segmentos na esfera celeste.
+func constellationFigures(cat []catalog.Star, cons []catalog.Constellation) []ConstellationFigure {
+	index := catalog.IndexByHR(cat)
+	figures := make([]ConstellationFigure, 0, len(cons))
+	for _, c := range cons {
+		center, ok := c.Center(index)
+		if !ok {
+			continue
+		}
+		fig := ConstellationFigure{Name: c.Name, Label: celestialPoint(center)}
+		for _, seg := range c.Segments(index) {
+			fig.Segments = append(fig.Segments, [2]rl.Vector3{
+				celestialPoint(seg[0].Direction()),
+				celestialPoint(seg[1].Direction()),
+			})
+		}
+		figures = append(figures, fig)
+	}
+	return figures
+}
+
+// skySegments devolve os traços das figuras como pares de direções equatoriais
+// J2000, para o planetário levar ao céu local a cada frame.
+func skySegments(cat []catalog.Star, cons []catalog.Constellation) [][2]astro.Vec3 {
+	index := catalog.IndexByHR(cat)
+	var segs [][2]astro.Vec3
+	for _, c := range cons {
+		for _, seg := range c.Segments(index) {
+			segs = append(segs, [2]astro.Vec3{seg[0].Direction(), seg[1].Direction()})
+		}
+	}
+	return segs
+}
+
+// celestialPoint leva uma direção equatorial J2000 para a esfera celeste da cena.
+func celestialPoint(dir astro.Vec3) rl.Vector3 {
+	return eclipticToScene(astro.EquatorialToEcliptic(dir).Scale(celestialSphereRadius))
+}
+
+// randomStars gera estrelas cintilantes aleatórias numa casca esférica distante.
+func randomStars(rng *rand.Rand, count int) []Star {
+	stars := make([]Star, count)
+	for i := 0; i < count; i++ {
+		r := 600 + rng.Float64()*200
+		theta := rng.Float64() * 2 * math.Pi
+		phi := rng.Float64() * math.Pi
+		x := r * math.Sin(phi) * math.Cos(theta)
+		y := r * math.Cos(phi)
+		z := r * math.Sin(phi) * math.Sin(theta)
+		stars[i] = Star{
+			Position:       rl.NewVector3(float32(x), float32(y), float32(z)),
+			Phase:          rng.Float64() * 2 * math.Pi,
+			Speed:          0.005 + rng.Float64()*0.005,
+			BaseBrightness: 100 + rng.Intn(155),
+			Color:          rl.White,
+			Size:           1,
+			Twinkle:        true,
+		}
+	}
+	return stars
+}
+
+// eclipticToScene converte um vetor da eclíptica para a cena. O plano orbital é o
+// XZ do raylib e o polo norte eclíptico aponta para -Y, de modo que os ângulos
+// crescentes da simulação correspondem ao movimento direto dos planetas.
+func eclipticToScene(v astro.Vec3) rl.Vector3 {
+	return rl.NewVector3(float32(v.X), float32(-v.Z), float32(v.Y))
+}
+
 // resetComet define uma nova posição e direção para o cometa.
 // O cometa é posicionado aleatoriamente em um anel na região externa (raio entre 600 e 1000)
 // e sua direção é calculada para apontar aproximadamente para o centro (0,0,0).
 func resetComet(sim *Simulation) {
 	rMin, rMax := 600.0, 1000.0
-	radius := rMin + rand.Float64()*(rMax-rMin)
-	angle := rand.Float64() * 2 * math.Pi
+	radius := rMin + sim.rng.Float64()*(rMax-rMin)
+	angle := sim.rng.Float64() * 2 * math.Pi
 	sim.Comet.Position = rl.NewVector3(float32(radius*math.Cos(angle)), 0, float32(radius*math.Sin(angle)))
 	// Direção para o centro
 	sim.Comet.Angle = math.Atan2(-float64(sim.Comet.Position.Z), -float64(sim.Comet.Position.X))
 	sim.Comet.Speed = 4.0
-	sim.Comet.TailPoints = make([]rl.Vector3, 0)
+	sim.Comet.Tail.Reset()
 }
 
 // distance retorna a distância Euclidiana entre dois pontos 3D.
@@ -267,18 +530,45 @@ func distance(v1, v2 rl.Vector3) float32 {
 	return float32(math.Sqrt(float64(dx*dx + dy*dy + dz*dz)))
 }
 
+// Position retorna a posição do planeta na cena (plano XZ, Sol na origem).
+func (p *Planet) Position() rl.Vector3 {
+	return rl.NewVector3(float32(p.OrbitRadius*math.Cos(p.Angle)), 0, float32(p.OrbitRadius*math.Sin(p.Angle)))
+}
+
+// Position retorna a posição da lua na cena, dada a posição do seu planeta.
+func (m *Moon) Position(planetPos rl.Vector3) rl.Vector3 {
+	return rl.Vector3Add(planetPos, eclipticToScene(m.Offset()))
+}
+
+// Offset devolve a posição da lua relativa ao planeta, na eclíptica da cena.
+func (m *Moon) Offset() astro.Vec3 {
+	s, c := math.Sincos(m.Angle - m.Node)
+	v := astro.Vec3{X: m.OrbitRadius * c, Y: m.OrbitRadius * s}
+	return v.RotateX(m.Inclination).RotateZ(m.Node)
+}
+
+// Velocity devolve a velocidade da lua relativa ao planeta (px/frame).
+func (m *Moon) Velocity() astro.Vec3 {
+	normal := astro.Vec3{Z: 1}.RotateX(m.Inclination).RotateZ(m.Node)
+	return normal.Cross(m.Offset()).Scale(m.OrbitSpeed)
+}
+
+// followMoon coloca a Lua da Terra na posição real da data jd: o nodo segue
+// a regressão média e o ângulo vem da direção geocêntrica da Lua, projetada
+// no plano inclinado.
+func (m *Moon) followMoon(jd float64) {
+	m.Node = astro.MoonNode(jd)
+	v := astro.GeocentricMoon(jd).RotateZ(-m.Node).RotateX(-m.Inclination)
+	m.Angle = m.Node + math.Atan2(v.Y, v.X)
+}
+
 // CheckCollisions verifica se o cometa colide com algum objeto (planeta ou asteroide)
 // (exceto o Sol). Se houver colisão, ativa um efeito de explosão e reinicia o cometa.
 func (sim *Simulation) CheckCollisions() {
 	cometRadius := float32(4)
 	// Colisão com planetas
 	for _, p := range sim.Planets {
-		planetPos := rl.NewVector3(
-			float32(p.OrbitRadius*math.Cos(p.Angle)),
-			0,
-			float32(p.OrbitRadius*math.Sin(p.Angle)),
-		)
-		if distance(sim.Comet.Position, planetPos) < (cometRadius + p.Radius) {
+		if distance(sim.Comet.Position, p.Position()) < (cometRadius + p.Radius) {
 			sim.ExplosionActive = true
 			sim.ExplosionTime = 0
 			sim.ExplosionPosition = sim.Comet.Position
@@ -307,6 +597,8 @@ func (sim *Simulation) CheckCollisions() {
 func (sim *Simulation) Update() {
 	dt := 1.0 / 60.0
 	sim.Time += dt
+	sim.Ticks++
+	sim.JD += sim.TimeScale
 
 	// Atualiza as fases das estrelas (cintilação)
 	for i := range sim.Stars {
@@ -321,11 +613,7 @@ func (sim *Simulation) Update() {
 	// Atualiza a posição e o rastro do cometa
 	sim.Comet.Position.X += float32(sim.Comet.Speed * math.Cos(sim.Comet.Angle))
 	sim.Comet.Position.Z += float32(sim.Comet.Speed * math.Sin(sim.Comet.Angle))
-	// Atualiza o rastro: adiciona a posição atual no início da lista
-	sim.Comet.TailPoints = append([]rl.Vector3{sim.Comet.Position}, sim.Comet.TailPoints...)
-	if len(sim.Comet.TailPoints) > sim.Comet.TailMaxLength {
-		sim.Comet.TailPoints = sim.Comet.TailPoints[:sim.Comet.TailMaxLength]
-	}
+	sim.Comet.Tail.Add(sim.Comet.Position, sim.Time)
 	// Se o cometa sair da região, reinicia
 	if sim.Comet.Position.X > 800 || sim.Comet.Position.Z > 800 {
 		resetComet(sim)
@@ -333,15 +621,39 @@ func (sim *Simulation) Update() {
 
 	// Atualiza os ângulos dos planetas e suas luas
 	for _, p := range sim.Planets {
-		p.Angle += p.OrbitSpeed
-		for _, m := range p.Moons {
+		if b, ok := astro.BodyByName(p.Name); ok && sim.Ephemeris {
+			p.Angle, _ = astro.CartesianToSpherical(astro.HeliocentricPosition(b, sim.JD))
+		} else {
+			p.Angle += p.OrbitSpeed
+		}
+		for i, m := range p.Moons {
+			if b, ok := astro.BodyByName(p.Name); ok && b == astro.Earth && i == 0 && sim.Ephemeris {
+				m.followMoon(sim.JD)
+				continue
+			}
 			m.Angle += m.OrbitSpeed
+			m.Node += m.NodeRate * sim.TimeScale
 		}
 	}
 
+	// Integra os troianos com o planeta já na nova posição
+	sim.updateTrojans()
+
+	// Janela de lançamento e sonda do planejador de transferências
+	sim.updateTransfer()
+
+	// Move a nave, executa os nós vencidos e refaz a previsão
+	sim.updateCraft()
+
 	// Verifica colisões e dispara explosão se necessário
 	sim.CheckCollisions()
 
+	// Avisa quando luas e cometa cruzam esferas de influência e de Hill
+	sim.updateSpheres()
+
+	// Rastros dos corpos, no referencial da cena
+	sim.updateTrails()
+
 	// Atualiza o tempo da explosão, se ativo
 	if sim.ExplosionActive {
 		sim.ExplosionTime += dt
@@ -424,185 +736,2595 @@ func generateRingMesh(innerRadius, outerRadius float32, segments int) rl.Mesh {
 	}
 }
 
-// Desenha a cena 3D usando as funções nativas (esferas, modelo do anel e efeitos)
-func (sim *Simulation) Draw3D(ringModel rl.Model) {
-	// Desenha o Sol
-	drawSphere(rl.NewVector3(0, 0, 0), sim.SunRadius, rl.Yellow)
-
-	// OBS.: As órbitas dos planetas foram removidas conforme solicitado.
+// ─────────────────────────────────────────────
+// Troianos e referencial girante
 
-	// Desenha os planetas e suas luas
-	for _, p := range sim.Planets {
-		x := float32(p.OrbitRadius * math.Cos(p.Angle))
-		z := float32(p.OrbitRadius * math.Sin(p.Angle))
-		planetPos := rl.NewVector3(x, 0, z)
-		drawSphere(planetPos, p.Radius, p.Color)
+const (
+	trojanSubsteps    = 4    // subpassos de integração por frame
+	trojanPathSeconds = 15.0 // duração do rastro de cada troiano (900 frames)
+)
 
-		// Se for Saturn, desenha os anéis
-		if p.Name == "Saturn" {
-			rl.DrawModelEx(ringModel, planetPos, rl.NewVector3(1, 0, 0), 25, rl.NewVector3(p.Radius*3, 1, p.Radius*3), rl.LightGray)
-		}
-		// Desenha as luas
-		for _, m := range p.Moons {
-			mx := planetPos.X + float32(m.OrbitRadius*math.Cos(m.Angle))
-			mz := planetPos.Z + float32(m.OrbitRadius*math.Sin(m.Angle))
-			moonPos := rl.NewVector3(mx, 0, mz)
-			drawSphere(moonPos, m.Radius, m.Color)
-		}
+// SpawnTrojans cria uma população em torno de L4 e L5 do planeta (órbitas de
+// girino) e algumas partículas perto de L3 (órbitas de ferradura). Todas partem
+// em repouso no referencial girante.
+func (sim *Simulation) SpawnTrojans(p *Planet) {
+	sim.TrojanHost = p
+	sim.Trojans = sim.Trojans[:0]
+	deg := math.Pi / 180
+	add := func(offset, spreadAngle, spreadRadius float64) {
+		angle := p.Angle + offset + (sim.rng.Float64()*2-1)*spreadAngle
+		r := p.OrbitRadius * (1 + (sim.rng.Float64()*2-1)*spreadRadius)
+		pos := astro.Vec3{X: r * math.Cos(angle), Y: r * math.Sin(angle)}
+		vel := astro.Vec3{X: -pos.Y, Y: pos.X}.Scale(p.OrbitSpeed)
+		sim.Trojans = append(sim.Trojans, Trojan{Position: pos, Velocity: vel, Path: trail.New[rl.Vector3](trojanPathSeconds)})
 	}
-
-	// Desenha os asteroides
-	for _, a := range sim.Asteroids {
-		ax := float32(a.OrbitRadius * math.Cos(a.Angle))
-		az := float32(a.OrbitRadius * math.Sin(a.Angle))
-		asteroidPos := rl.NewVector3(ax, 0, az)
-		drawSphere(asteroidPos, a.Radius, rl.Gray)
+	for i := 0; i < 30; i++ {
+		add(60*deg, 12*deg, 0.01)  // L4, à frente do planeta
+		add(-60*deg, 12*deg, 0.01) // L5, atrás do planeta
+	}
+	for i := 0; i < 10; i++ {
+		add(180*deg, 8*deg, 0.002) // L3, do outro lado do Sol
 	}
+}
 
-	// Desenha o rastro do cometa (meteoro)
-	// Primeiro, desenha esferas com alfa decrescente
-	for i := 0; i < len(sim.Comet.TailPoints)-1; i++ {
-		alpha := uint8(200 * (1 - float32(i)/float32(len(sim.Comet.TailPoints))))
-		col := rl.NewColor(255, 255, 255, alpha)
-		drawSphere(sim.Comet.TailPoints[i], 2, col)
+// updateTrojans integra os troianos sob a gravidade do Sol e do planeta
+// hospedeiro, em coordenadas heliocêntricas (com o termo indireto, já que o
+// Sol da simulação fica parado na origem). A integração supõe que o planeta
+// anda OrbitSpeed por frame; com as efemérides ele segue a data JD, que
+// muda de ritmo e salta, então os troianos são desligados.
+func (sim *Simulation) updateTrojans() {
+	p := sim.TrojanHost
+	if p == nil {
+		return
+	}
+	if sim.Ephemeris {
+		sim.TrojanHost, sim.Trojans = nil, nil
+		sim.Notifications = append(sim.Notifications, Notification{Text: "Troianos desligados pelas efemérides (J)", Time: sim.Time})
+		return
 	}
-	// Em seguida, desenha linhas conectando os pontos do rastro para um efeito contínuo
-	for i := 0; i < len(sim.Comet.TailPoints)-1; i++ {
-		alpha := uint8(200 * (1 - float32(i)/float32(len(sim.Comet.TailPoints))))
-		col := rl.NewColor(255, 255, 255, alpha)
-		rl.DrawLine3D(sim.Comet.TailPoints[i], sim.Comet.TailPoints[i+1], col)
+	muSun, muPlanet := planetMu(p)
+	start := p.Angle - p.OrbitSpeed // ângulo no início deste frame
+	planetAt := func(t float64) astro.Vec3 {
+		a := start + p.OrbitSpeed*t
+		return astro.Vec3{X: p.OrbitRadius * math.Cos(a), Y: p.OrbitRadius * math.Sin(a)}
+	}
+	accel := func(t float64, r astro.Vec3) astro.Vec3 {
+		rp := planetAt(t)
+		a := astro.PointMassAccel(r, astro.Vec3{}, muSun)
+		a = a.Add(astro.PointMassAccel(r, rp, muPlanet))
+		return a.Sub(rp.Scale(muPlanet / math.Pow(rp.Norm(), 3)))
 	}
 
-	// Desenha o cometa (meteoro)
-	drawSphere(sim.Comet.Position, 4, rl.White)
+	dt := 1.0 / trojanSubsteps
+	for i := range sim.Trojans {
+		tr := &sim.Trojans[i]
+		for k := 0; k < trojanSubsteps; k++ {
+			tr.Position, tr.Velocity = astro.LeapfrogStep(float64(k)*dt, tr.Position, tr.Velocity, dt, accel)
+		}
+		// Guarda a posição no referencial que gira com o planeta
+		tr.Path.Add(corotate(eclipticToScene(tr.Position), p.Angle), sim.Time)
+	}
+}
 
-	// Desenha as estrelas cintilantes
-	for _, star := range sim.Stars {
-		brightness := float32(128 + 127*math.Sin(star.Phase))
-		if brightness < 0 {
-			brightness = 0
-		} else if brightness > 255 {
-			brightness = 255
+// corotate gira um ponto da cena em torno do eixo Y, levando o ângulo theta
+// (na convenção dos ângulos da simulação) para o eixo +X.
+func corotate(p rl.Vector3, theta float64) rl.Vector3 {
+	s, c := math.Sincos(theta)
+	return rl.NewVector3(
+		p.X*float32(c)+p.Z*float32(s),
+		p.Y,
+		-p.X*float32(s)+p.Z*float32(c),
+	)
+}
+
+// NextRotatingPlanet percorre os planetas para o referencial girante; depois
+// do último, volta ao referencial inercial escolhido com F.
+func (sim *Simulation) NextRotatingPlanet() {
+	sim.resetTrails()
+	if sim.RotatingPlanet == nil {
+		sim.RotatingPlanet = sim.Planets[0]
+		return
+	}
+	for i, p := range sim.Planets {
+		if p == sim.RotatingPlanet {
+			if i+1 < len(sim.Planets) {
+				sim.RotatingPlanet = sim.Planets[i+1]
+			} else {
+				sim.RotatingPlanet = nil
+			}
+			return
 		}
-		col := rl.NewColor(255, 255, 255, uint8(brightness))
-		drawSphere(star.Position, 1, col)
 	}
+}
 
-	// Se uma explosão estiver ativa, desenha o efeito de explosão
-	if sim.ExplosionActive {
-		maxExplosionRadius := float32(30)
-		explosionRadius := float32(sim.ExplosionTime/sim.ExplosionDuration) * maxExplosionRadius
-		alpha := uint8(255 * (1 - float32(sim.ExplosionTime)/float32(sim.ExplosionDuration)))
-		explosionColor := rl.NewColor(255, 200, 0, alpha)
-		rl.DrawSphere(sim.ExplosionPosition, explosionRadius, explosionColor)
+// FrameName descreve o referencial em uso na cena.
+func (sim *Simulation) FrameName() string {
+	if sim.RotatingPlanet != nil {
+		return "Girante com " + sim.RotatingPlanet.Name
 	}
+	return sim.Frame.String()
 }
 
-func main() {
-	// Define a flag para full screen antes de inicializar a janela
-	rl.SetConfigFlags(rl.FlagFullscreenMode)
+// drawTrojans desenha os troianos e, se a cena gira com o planeta hospedeiro,
+// os caminhos que eles traçaram nesse referencial.
+func (sim *Simulation) drawTrojans() {
+	if sim.TrojanHost == nil {
+		return
+	}
+	showPaths := sim.RotatingPlanet == sim.TrojanHost
+	for _, tr := range sim.Trojans {
+		drawSphere(sim.toFrame(eclipticToScene(tr.Position)), 1.2, rl.NewColor(255, 170, 80, 255))
+		if !showPaths {
+			continue
+		}
+		tr.Path.Segments(sim.Time, func(a, b rl.Vector3, age float64) {
+			rl.DrawLine3D(a, b, rl.NewColor(255, 170, 80, uint8(30+150*(1-age))))
+		})
+	}
+}
 
-	// Obtém a resolução do monitor principal para definir a janela full screen
-	screenWidth := int32(rl.GetMonitorWidth(0))
-	screenHeight := int32(rl.GetMonitorHeight(0))
-	rl.InitWindow(screenWidth, screenHeight, "Simulação 3D Realista do Sistema Solar - Câmeras, Física & Colisões")
-	defer rl.CloseWindow()
-	rl.SetTargetFPS(60)
+// ─────────────────────────────────────────────
+// Nave espacial e nós de manobra
 
-	// Carrega a imagem de fundo (certifique-se de que o arquivo "space.jpg" esteja na pasta "resources")
-	backgroundTexture := rl.LoadTexture("space.jpg")
-	defer rl.UnloadTexture(backgroundTexture)
+const (
+	// sceneSunMu é o μ do Sol na escala da cena (pixels³/frame²), calibrado
+	// para que a órbita circular da Terra da simulação seja kepleriana.
+	sceneSunMu = 0.02 * 0.02 * 160 * 160 * 160
 
-	// Cria a câmera 3D com parâmetros iniciais (modo normal)
-	camera := rl.Camera3D{
-		Position:   rl.NewVector3(0, 300, 600),
-		Target:     rl.NewVector3(0, 0, 0),
-		Up:         rl.NewVector3(0, 1, 0),
-		Fovy:       45,
-		Projection: rl.CameraPerspective,
-	}
+	craftSubsteps      = 4    // subpassos de integração por frame
+	predictionFrames   = 2000 // horizonte da trajetória prevista
+	predictionSubsteps = 2
+	predictionStride   = 4 // frames entre pontos guardados na previsão
+)
 
-	// Salvamos o estado normal da câmera para restaurá-lo depois do modo Top View.
-	normalCamera := camera
+// sunEarthMasses é a massa do Sol em massas terrestres.
+const sunEarthMasses = 332946.0
 
-	// Variável que guarda o modo de câmera para os controles normais (Orbital ou Livre)
-	currentCameraMode := CameraOrbital
+// Modelo de gravidade da cena: cada órbita circular da simulação é kepleriana
+// para o μ total ω²r³ do seu próprio par (Sol e planeta, ou planeta e lua),
+// repartido entre os dois corpos pela razão de massas real. Todos os μ estão
+// em pixels³/frame².
 
-	// Variável que indica se o modo Top View está ativo
-	topViewEnabled := false
+// splitMu reparte o μ total de uma órbita circular entre o primário e o
+// secundário, pela razão de massas real.
+func splitMu(omega, r, primaryMass, secondaryMass float64) (muPrimary, muSecondary float64) {
+	total := astro.CircularMu(omega, r)
+	ratio := secondaryMass / (primaryMass + secondaryMass)
+	return total * (1 - ratio), total * ratio
+}
 
-	// Gera o mesh e o modelo para o anel de Saturno
-	ringMesh := generateRingMesh(1.5, 2.0, 100)
-	ringModel := rl.LoadModelFromMesh(ringMesh)
-	defer rl.UnloadModel(ringModel)
+// planetMu devolve o μ do Sol e o do planeta tirados da órbita do planeta.
+func planetMu(p *Planet) (muSun, muPlanet float64) {
+	return splitMu(p.OrbitSpeed, p.OrbitRadius, sunEarthMasses, p.Mass)
+}
+
+// moonMu devolve o μ do planeta e o da lua tirados da órbita da lua.
+func moonMu(p *Planet, m *Moon) (muPlanet, muMoon float64) {
+	return splitMu(m.OrbitSpeed, m.OrbitRadius, p.Mass, m.Mass)
+}
 
-	sim := NewSimulation()
+// sceneGM devolve o μ de um planeta na cena.
+func sceneGM(p *Planet) float64 {
+	_, mu := planetMu(p)
+	return mu
+}
 
-	for !rl.WindowShouldClose() {
-		sim.Update()
+// sceneSOI é o raio da esfera de influência do planeta na cena.
+func sceneSOI(p *Planet) float64 {
+	muSun, muPlanet := planetMu(p)
+	return astro.SphereOfInfluence(p.OrbitRadius, muPlanet, muSun)
+}
 
-		// Alterna entre o modo Top View e o normal ao pressionar a tecla P.
-		// No modo Top View, a câmera fica fixa, sem reagir ao mouse.
-		if rl.IsKeyPressed(rl.KeyP) {
-			if !topViewEnabled {
-				normalCamera = camera
-				camera.Position = rl.NewVector3(0, 800, 0)
-				camera.Target = rl.NewVector3(0, 0, 0)
-				camera.Up = rl.NewVector3(0, 0, -1)
-				camera.Projection = rl.CameraPerspective
-				camera.Fovy = 45
-				topViewEnabled = true
-			} else {
-				topViewEnabled = false
-				camera = normalCamera
-			}
+// sceneHill é o raio da esfera de Hill do planeta na cena.
+func sceneHill(p *Planet) float64 {
+	muSun, muPlanet := planetMu(p)
+	return astro.HillRadius(p.OrbitRadius, muPlanet, muSun)
+}
+
+// planetState devolve a posição e a velocidade eclípticas de um planeta da
+// cena t frames depois do estado atual, supondo que ele ande OrbitSpeed por
+// frame. Com as efemérides isso não vale, e a nave e o sobrevoo, que dependem
+// dele, são desligados (veja updateCraft).
+func planetState(p *Planet, t float64) (astro.Vec3, astro.Vec3) {
+	s, c := math.Sincos(p.Angle + p.OrbitSpeed*t)
+	pos := astro.Vec3{X: p.OrbitRadius * c, Y: p.OrbitRadius * s}
+	return pos, astro.Vec3{X: -pos.Y, Y: pos.X}.Scale(p.OrbitSpeed)
+}
+
+// sceneGravity devolve a aceleração gravitacional do Sol e dos planetas na
+// cena em r, t frames depois do estado atual dos planetas.
+func (sim *Simulation) sceneGravity(t float64, r astro.Vec3) astro.Vec3 {
+	a := astro.PointMassAccel(r, astro.Vec3{}, sceneSunMu)
+	for _, p := range sim.Planets {
+		pos, _ := planetState(p, t)
+		a = a.Add(astro.PointMassAccel(r, pos, sceneGM(p)))
+	}
+	return a
+}
+
+// LaunchCraft cria uma nave em órbita circular logo além da Terra.
+func (sim *Simulation) LaunchCraft() {
+	earth := sim.planetByBody(astro.Earth)
+	if earth == nil {
+		earth = sim.Planets[0]
+	}
+	pos := sceneToEcliptic(earth.Position())
+	pos = pos.Scale(1 + 15/pos.Norm())
+	speed := math.Sqrt(sceneSunMu / pos.Norm())
+	sim.Craft = &Spacecraft{
+		Name:         "Sonda",
+		Position:     pos,
+		Velocity:     astro.Vec3{X: -pos.Y, Y: pos.X}.Unit().Scale(speed),
+		DryMass:      1000,
+		Propellant:   500,
+		ExhaustSpeed: 1.5,
+	}
+	sim.SelectedNode = -1
+	sim.predictCraft()
+}
+
+// executeBurn aplica um nó de manobra, limitado pelo propelente que resta.
+func (c *Spacecraft) executeBurn(n ManeuverNode) {
+	dv := n.DeltaV()
+	if dv == 0 {
+		return
+	}
+	mass := c.DryMass + c.Propellant
+	burned := astro.PropellantFor(mass, dv, c.ExhaustSpeed)
+	if burned > c.Propellant {
+		burned = c.Propellant
+	}
+	scale := astro.DeltaVFor(mass, burned, c.ExhaustSpeed) / dv
+	c.Propellant -= burned
+	burn := astro.BurnVector(c.Position, c.Velocity, n.Prograde, n.Normal, n.Radial)
+	c.Velocity = c.Velocity.Add(burn.Scale(scale))
+}
+
+// updateCraft integra a nave por um frame e executa os nós vencidos. A
+// gravidade dos planetas vem de planetState, que não segue as efemérides;
+// com elas ligadas a nave e o sobrevoo são desligados, como os troianos.
+func (sim *Simulation) updateCraft() {
+	c := sim.Craft
+	if c == nil {
+		return
+	}
+	if sim.Ephemeris {
+		sim.Craft, sim.Flyby = nil, nil
+		sim.Notifications = append(sim.Notifications, Notification{Text: "Nave desligada pelas efemérides (J)", Time: sim.Time})
+		return
+	}
+	dt := 1.0 / craftSubsteps
+	for k := 0; k < craftSubsteps; k++ {
+		c.Position, c.Velocity = astro.LeapfrogStep(float64(k)*dt-1, c.Position, c.Velocity, dt, sim.sceneGravity)
+	}
+	if sim.trackFlyby() {
+		return
+	}
+	for len(c.Nodes) > 0 && c.Nodes[0].Time <= sim.Ticks {
+		c.executeBurn(c.Nodes[0])
+		c.Nodes = c.Nodes[1:]
+		if sim.SelectedNode >= 0 {
+			sim.SelectedNode--
 		}
+	}
+	sim.predictCraft()
+}
 
-		// Se não estiver no modo Top View, atualiza a câmera com base nas entradas do usuário.
-		if !topViewEnabled {
-			if rl.IsKeyPressed(rl.KeyOne) {
-				currentCameraMode = CameraOrbital
-			}
-			if rl.IsKeyPressed(rl.KeyTwo) {
-				currentCameraMode = CameraFree
-			}
-			rl.UpdateCamera(&camera, rl.CameraMode(currentCameraMode))
+// predictCraft integra uma cópia da nave à frente, aplicando os nós futuros,
+// e guarda um trecho da trajetória para cada nó.
+func (sim *Simulation) predictCraft() {
+	c := sim.Craft
+	ghost := *c
+	ghost.Nodes = nil
+	c.Prediction = c.Prediction[:0]
+	segment := []rl.Vector3{eclipticToScene(ghost.Position)}
+	next := 0
+	dt := 1.0 / predictionSubsteps
+	for f := 0; f < predictionFrames; f++ {
+		for k := 0; k < predictionSubsteps; k++ {
+			t := float64(f) + float64(k)*dt
+			ghost.Position, ghost.Velocity = astro.LeapfrogStep(t, ghost.Position, ghost.Velocity, dt, sim.sceneGravity)
+		}
+		if next < len(c.Nodes) && c.Nodes[next].Time <= sim.Ticks+float64(f+1) {
+			ghost.executeBurn(c.Nodes[next])
+			next++
+			segment = append(segment, eclipticToScene(ghost.Position))
+			c.Prediction = append(c.Prediction, segment)
+			segment = []rl.Vector3{eclipticToScene(ghost.Position)}
+			continue
 		}
+		if f%predictionStride == 0 {
+			segment = append(segment, eclipticToScene(ghost.Position))
+		}
+	}
+	c.Prediction = append(c.Prediction, segment)
+}
 
-		rl.BeginDrawing()
-		// Limpa o frame
-		rl.ClearBackground(rl.Black)
+// Flyby é a análise da passagem da nave pela esfera de influência de um planeta.
+type Flyby struct {
+	Planet    *Planet
+	Inside    bool         // a nave ainda está dentro da esfera de influência
+	Path      []astro.Vec3 // posições relativas ao planeta (o trecho hiperbólico)
+	Periapsis float64      // menor distância ao centro do planeta
+	VInfIn    astro.Vec3   // velocidade relativa na entrada
+	VInfOut   astro.Vec3   // velocidade relativa na saída
+	SpeedIn   float64      // velocidade heliocêntrica na entrada
+	SpeedOut  float64      // velocidade heliocêntrica na saída
+}
 
-		// Desenha a imagem de fundo esticada para cobrir toda a tela
-		rl.DrawTexturePro(
-			backgroundTexture,
-			rl.NewRectangle(0, 0, float32(backgroundTexture.Width), float32(backgroundTexture.Height)),
-			rl.NewRectangle(0, 0, float32(screenWidth), float32(screenHeight)),
-			rl.NewVector2(0, 0),
-			0,
-			rl.White,
-		)
+// TurnAngle é o ângulo entre as velocidades relativas de entrada e de saída.
+func (f *Flyby) TurnAngle() float64 {
+	cos := f.VInfIn.Unit().Dot(f.VInfOut.Unit())
+	return math.Acos(math.Max(-1, math.Min(1, cos)))
+}
 
-		rl.BeginMode3D(camera)
-		sim.Draw3D(ringModel)
-		rl.EndMode3D()
+// DeltaV é o módulo da variação de velocidade heliocêntrica dada pelo
+// planeta, v∞(saída) − v∞(entrada).
+func (f *Flyby) DeltaV() float64 {
+	return f.VInfOut.Sub(f.VInfIn).Norm()
+}
 
-		// Exibe informações na tela
-		modeText := ""
-		if topViewEnabled {
-			modeText = "Top View"
-		} else {
-			if currentCameraMode == CameraOrbital {
-				modeText = "Orbital"
-			} else {
-				modeText = "Livre"
+// trackFlyby acompanha a nave nas esferas de influência dos planetas. Devolve
+// true se a nave colidiu com o planeta e foi destruída.
+func (sim *Simulation) trackFlyby() bool {
+	c := sim.Craft
+	for _, p := range sim.Planets {
+		pos, vel := planetState(p, 0)
+		rel := c.Position.Sub(pos)
+		d := rel.Norm()
+		inside := d < sceneSOI(p)
+		f := sim.Flyby
+		busy := f != nil && f.Inside && f.Planet != p // já dentro de outra esfera
+		switch {
+		case busy:
+		case inside && (f == nil || f.Planet != p || !f.Inside):
+			sim.Flyby = &Flyby{
+				Planet:    p,
+				Inside:    true,
+				Periapsis: d,
+				VInfIn:    c.Velocity.Sub(vel),
+				SpeedIn:   c.Velocity.Norm(),
 			}
+		case inside:
+			f.Path = append(f.Path, rel)
+			f.Periapsis = math.Min(f.Periapsis, d)
+		case f != nil && f.Planet == p && f.Inside:
+			f.Inside = false
+			f.VInfOut = c.Velocity.Sub(vel)
+			f.SpeedOut = c.Velocity.Norm()
 		}
-		rl.DrawText("Simulação 3D Realista do Sistema Solar", 10, 10, 20, rl.White)
-		rl.DrawText("Modo da Câmera: "+modeText, 10, 40, 20, rl.White)
-		rl.DrawText("Pressione 1: Orbital | 2: Livre (modo normal)", 10, 70, 20, rl.White)
-		rl.DrawText("Pressione P: Alternar Top View", 10, 100, 20, rl.White)
+		if d < float64(p.Radius) {
+			sim.ExplosionActive = true
+			sim.ExplosionTime = 0
+			sim.ExplosionPosition = eclipticToScene(c.Position)
+			sim.Craft = nil
+			return true
+		}
+	}
+	return false
+}
 
-		rl.EndDrawing()
+// Sobrevoos: a periapse fica a flybyPeriapsis raios do planeta e a sonda
+// chega com v∞ = flybyVInf da velocidade orbital do planeta. Com a razão de
+// massas real as esferas de influência são pequenas, e a chegada lenta dá um
+// desvio visível.
+const (
+	flybyPeriapsis = 1.05
+	flybyVInf      = 0.2
+)
+
+// NextFlybyTarget escolhe o próximo planeta em que cabe um sobrevoo (a
+// periapse precisa ficar dentro da esfera de influência). Devolve nil se não
+// houver nenhum.
+func (sim *Simulation) NextFlybyTarget() *Planet {
+	p := sim.FlybyTarget
+	for range sim.Planets {
+		if p == nil {
+			p = sim.Planets[0]
+		} else {
+			p = sim.cyclePlanet(p, nil)
+		}
+		if flybyPeriapsis*float64(p.Radius) < sceneSOI(p) {
+			return p
+		}
+	}
+	return nil
+}
+
+// LaunchFlyby prepara uma sonda que passa pelo lado de trás do planeta (em
+// relação ao seu movimento), onde a passagem a acelera. O estado na periapse
+// é montado primeiro e integrado para trás até o instante atual; como o
+// leapfrog é reversível, a sonda refaz o caminho e chega à periapse exata.
+func (sim *Simulation) LaunchFlyby(p *Planet) {
+	mu := sceneGM(p)
+	vInf := flybyVInf * p.OrbitSpeed * p.OrbitRadius
+	rp := flybyPeriapsis * float64(p.Radius)
+	arrival := math.Ceil(1.2 * sceneSOI(p) / vInf) // frames até a periapse
+
+	pos, vel := planetState(p, arrival)
+	radial, along := pos.Unit(), vel.Unit()
+	r := pos.Sub(along.Scale(rp))
+	v := vel.Sub(radial.Scale(math.Sqrt(vInf*vInf + 2*mu/rp)))
+	dt := 1.0 / craftSubsteps
+	for i := int(arrival) * craftSubsteps; i > 0; i-- {
+		r, v = astro.LeapfrogStep(float64(i)*dt, r, v, -dt, sim.sceneGravity)
+	}
+
+	sim.Craft = &Spacecraft{
+		Name:         "Sonda de sobrevoo",
+		Position:     r,
+		Velocity:     v,
+		DryMass:      1000,
+		Propellant:   500,
+		ExhaustSpeed: 1.5,
+	}
+	sim.Flyby = nil
+	sim.FlybyTarget = p
+	sim.SelectedNode = -1
+	sim.predictCraft()
+}
+
+// drawFlyby desenha o trecho hiperbólico do último sobrevoo em torno da
+// posição atual do planeta, com a esfera de influência e a periapse.
+func (sim *Simulation) drawFlyby() {
+	f := sim.Flyby
+	if f == nil || len(f.Path) < 2 {
+		return
+	}
+	center := f.Planet.Position()
+	at := func(rel astro.Vec3) rl.Vector3 {
+		return sim.toFrame(rl.Vector3Add(center, eclipticToScene(rel)))
+	}
+	col := rl.NewColor(255, 120, 80, 220)
+	closest := 0
+	for i := 0; i+1 < len(f.Path); i++ {
+		rl.DrawLine3D(at(f.Path[i]), at(f.Path[i+1]), col)
+		if f.Path[i].Norm() < f.Path[closest].Norm() {
+			closest = i
+		}
+	}
+	rl.DrawSphereWires(at(f.Path[closest]), 1.5, 4, 6, col)
+	rl.DrawCircle3D(sim.toFrame(center), float32(sceneSOI(f.Planet)), rl.NewVector3(1, 0, 0), 90, rl.NewColor(255, 120, 80, 60))
+}
+
+// FlybySummary descreve o último sobrevoo para o HUD.
+func (sim *Simulation) FlybySummary() string {
+	f := sim.Flyby
+	altitude := f.Periapsis - float64(f.Planet.Radius)
+	if f.Inside {
+		return fmt.Sprintf("Sobrevoo de %s em andamento: periapse até agora %.1f px (altitude %.1f)", f.Planet.Name, f.Periapsis, altitude)
+	}
+	return fmt.Sprintf("Sobrevoo de %s: altitude da periapse %.1f px | desvio %.1f° | dv heliocêntrico %.3f px/frame | velocidade %.3f -> %.3f",
+		f.Planet.Name, altitude, f.TurnAngle()*180/math.Pi, f.DeltaV(), f.SpeedIn, f.SpeedOut)
+}
+
+// FlybyModel descreve o modelo de gravidade do sobrevoo para o HUD.
+func (sim *Simulation) FlybyModel() string {
+	p := sim.Flyby.Planet
+	return fmt.Sprintf("μ de %s = %.3f px³/frame² (ω²r³ da órbita × razão de massas real) | esfera de influência %.1f px",
+		p.Name, sceneGM(p), sceneSOI(p))
+}
+
+// AddNode agenda um nó vazio 200 frames depois do último (ou de agora).
+func (sim *Simulation) AddNode() {
+	c := sim.Craft
+	t := sim.Ticks + 200
+	if n := len(c.Nodes); n > 0 {
+		t = c.Nodes[n-1].Time + 200
+	}
+	c.Nodes = append(c.Nodes, ManeuverNode{Time: t})
+	sim.SelectedNode = len(c.Nodes) - 1
+}
+
+// EditNode ajusta o nó selecionado: dv soma ao componente escolhido e dt move
+// o nó no tempo, mantendo a lista ordenada e o nó no futuro.
+func (sim *Simulation) EditNode(dv, dt float64) {
+	c := sim.Craft
+	if sim.SelectedNode < 0 || sim.SelectedNode >= len(c.Nodes) {
+		return
+	}
+	n := &c.Nodes[sim.SelectedNode]
+	switch sim.NodeComponent {
+	case 0:
+		n.Prograde += dv
+	case 1:
+		n.Normal += dv
+	case 2:
+		n.Radial += dv
+	}
+	lo := sim.Ticks + 1
+	if sim.SelectedNode > 0 {
+		lo = c.Nodes[sim.SelectedNode-1].Time + 1
+	}
+	hi := math.Inf(1)
+	if sim.SelectedNode+1 < len(c.Nodes) {
+		hi = c.Nodes[sim.SelectedNode+1].Time - 1
+	}
+	n.Time = math.Max(lo, math.Min(hi, n.Time+dt))
+}
+
+// RemoveNode apaga o nó selecionado.
+func (sim *Simulation) RemoveNode() {
+	c := sim.Craft
+	if sim.SelectedNode < 0 || sim.SelectedNode >= len(c.Nodes) {
+		return
+	}
+	c.Nodes = append(c.Nodes[:sim.SelectedNode], c.Nodes[sim.SelectedNode+1:]...)
+	if sim.SelectedNode >= len(c.Nodes) {
+		sim.SelectedNode = len(c.Nodes) - 1
+	}
+}
+
+// craftColors são as cores dos trechos da trajetória prevista, um por nó.
+var craftColors = []rl.Color{
+	rl.NewColor(230, 230, 230, 180),
+	rl.NewColor(255, 200, 80, 180),
+	rl.NewColor(120, 220, 255, 180),
+	rl.NewColor(255, 120, 200, 180),
+}
+
+// drawCraft desenha a nave, a trajetória prevista e os nós de manobra.
+func (sim *Simulation) drawCraft() {
+	c := sim.Craft
+	if c == nil {
+		return
+	}
+	for i, seg := range c.Prediction {
+		col := craftColors[i%len(craftColors)]
+		for k := 0; k+1 < len(seg); k++ {
+			rl.DrawLine3D(sim.toFrame(seg[k]), sim.toFrame(seg[k+1]), col)
+		}
+		// O fim de cada trecho, exceto o último, é um nó de manobra
+		if i < len(c.Prediction)-1 && len(seg) > 0 {
+			radius := float32(2)
+			if i == sim.SelectedNode {
+				radius = 3.5
+			}
+			rl.DrawSphereWires(sim.toFrame(seg[len(seg)-1]), radius, 4, 6, craftColors[(i+1)%len(craftColors)])
+		}
+	}
+	pos := sim.toFrame(eclipticToScene(c.Position))
+	rl.DrawCube(pos, 2.5, 2.5, 2.5, rl.NewColor(200, 255, 200, 255))
+}
+
+// CraftSummary descreve a nave e o nó selecionado para o HUD.
+func (sim *Simulation) CraftSummary() string {
+	c := sim.Craft
+	text := fmt.Sprintf("%s: %.0f kg de propelente | %d nós", c.Name, c.Propellant, len(c.Nodes))
+	if sim.SelectedNode >= 0 && sim.SelectedNode < len(c.Nodes) {
+		n := c.Nodes[sim.SelectedNode]
+		components := [3]string{"progrado", "normal", "radial"}
+		text += fmt.Sprintf(" | nó %d em %.0f frames: progrado %.3f normal %.3f radial %.3f (editando %s)",
+			sim.SelectedNode+1, n.Time-sim.Ticks, n.Prograde, n.Normal, n.Radial, components[sim.NodeComponent])
+	}
+	return text
+}
+
+// ─────────────────────────────────────────────
+// Esferas de influência (Laplace) e de Hill
+
+// moonName dá um nome à i-ésima lua do planeta.
+func moonName(p *Planet, i int) string {
+	return fmt.Sprintf("Lua %d de %s", i+1, p.Name)
+}
+
+// influenceSphere é uma esfera de influência ou de Hill de um corpo, relativa
+// ao seu primário (o Sol para os planetas, o planeta para as luas).
+type influenceSphere struct {
+	Owner   string
+	Hill    bool
+	Center  rl.Vector3
+	Radius  float64
+	Visible bool
+}
+
+// Name descreve a esfera para os avisos.
+func (s influenceSphere) Name() string {
+	if s.Hill {
+		return "esfera de Hill de " + s.Owner
+	}
+	return "esfera de influência de " + s.Owner
+}
+
+// spheres lista as esferas de todos os planetas e luas.
+func (sim *Simulation) spheres() []influenceSphere {
+	var out []influenceSphere
+	for _, p := range sim.Planets {
+		pos := p.Position()
+		out = append(out,
+			influenceSphere{Owner: p.Name, Center: pos, Radius: sceneSOI(p), Visible: p.ShowSpheres},
+			influenceSphere{Owner: p.Name, Hill: true, Center: pos, Radius: sceneHill(p), Visible: p.ShowSpheres})
+		for i, m := range p.Moons {
+			muPlanet, muMoon := moonMu(p, m)
+			center := m.Position(pos)
+			name := moonName(p, i)
+			out = append(out,
+				influenceSphere{Owner: name, Center: center, Radius: astro.SphereOfInfluence(m.OrbitRadius, muMoon, muPlanet), Visible: m.ShowSpheres},
+				influenceSphere{Owner: name, Hill: true, Center: center, Radius: astro.HillRadius(m.OrbitRadius, muMoon, muPlanet), Visible: m.ShowSpheres})
+		}
+	}
+	return out
+}
+
+// Notification é um aviso na tela, mostrado por notificationSeconds.
+type Notification struct {
+	Text string
+	Time float64 // sim.Time em que foi criado
+}
+
+const notificationSeconds = 5
+
+// updateSpheres confere se cada lua e o cometa estão dentro de cada esfera e
+// cria um aviso quando isso muda. Na primeira verificação só guarda o estado.
+func (sim *Simulation) updateSpheres() {
+	type tracer struct {
+		Name string
+		Pos  rl.Vector3
+	}
+	tracers := []tracer{{"Cometa", sim.Comet.Position}}
+	for _, p := range sim.Planets {
+		pos := p.Position()
+		for i, m := range p.Moons {
+			tracers = append(tracers, tracer{moonName(p, i), m.Position(pos)})
+		}
+	}
+	first := sim.insideSphere == nil
+	if first {
+		sim.insideSphere = make(map[string]bool)
+	}
+	for _, sph := range sim.spheres() {
+		for _, t := range tracers {
+			if t.Name == sph.Owner {
+				continue
+			}
+			key := t.Name + "|" + sph.Name()
+			inside := float64(distance(t.Pos, sph.Center)) < sph.Radius
+			if inside == sim.insideSphere[key] {
+				continue
+			}
+			sim.insideSphere[key] = inside
+			if first {
+				continue
+			}
+			verb := "saiu da"
+			if inside {
+				verb = "entrou na"
+			}
+			sim.Notifications = append(sim.Notifications, Notification{Text: t.Name + " " + verb + " " + sph.Name(), Time: sim.Time})
+		}
+	}
+	// Descarta os avisos antigos
+	for len(sim.Notifications) > 0 && sim.Time-sim.Notifications[0].Time > notificationSeconds {
+		sim.Notifications = sim.Notifications[1:]
+	}
+}
+
+// ToggleSpheres liga ou desliga as esferas do corpo escolhido com X ou, sem
+// escolha, de todos os planetas.
+func (sim *Simulation) ToggleSpheres() {
+	for _, p := range sim.Planets {
+		if sim.Selected == "" || sim.Selected == p.Name {
+			p.ShowSpheres = !p.ShowSpheres
+		}
+		for i, m := range p.Moons {
+			if sim.Selected == moonName(p, i) {
+				m.ShowSpheres = !m.ShowSpheres
+			}
+		}
+	}
+}
+
+// sphereDisplayScale aumenta só o desenho das esferas: com a razão de massas
+// real quase todas ficariam dentro do próprio corpo, cujo tamanho na cena já é
+// exagerado. Os avisos e a nave usam o raio real.
+const sphereDisplayScale = 10
+
+// drawSpheres desenha as esferas ligadas: a de influência em azul e a de Hill
+// em laranja, translúcidas, ampliadas por sphereDisplayScale.
+func (sim *Simulation) drawSpheres() {
+	for _, sph := range sim.spheres() {
+		if !sph.Visible {
+			continue
+		}
+		col := rl.NewColor(80, 140, 255, 30)
+		if sph.Hill {
+			col = rl.NewColor(255, 160, 60, 30)
+		}
+		center := sim.toFrame(sph.Center)
+		radius := float32(sph.Radius * sphereDisplayScale)
+		rl.DrawSphere(center, radius, col)
+		col.A = 70
+		rl.DrawSphereWires(center, radius, 8, 16, col)
+	}
+}
+
+// DrawSphereScale avisa a escala de desenho quando há esferas ligadas. Deve
+// ser chamada fora do modo 3D.
+func (sim *Simulation) DrawSphereScale(x, y int32) {
+	for _, sph := range sim.spheres() {
+		if sph.Visible {
+			rl.DrawText(fmt.Sprintf("Esferas desenhadas ×%d (só na tela; avisos e nave usam o raio real)", sphereDisplayScale), x, y, 18, rl.LightGray)
+			return
+		}
+	}
+}
+
+// DrawNotifications escreve os avisos recentes, esmaecendo com o tempo. Deve
+// ser chamada fora do modo 3D.
+func (sim *Simulation) DrawNotifications(x, y int32) {
+	for i, n := range sim.Notifications {
+		age := (sim.Time - n.Time) / notificationSeconds
+		alpha := uint8(255 * (1 - math.Min(1, age)))
+		rl.DrawText(n.Text, x, y+int32(i)*22, 18, rl.NewColor(255, 230, 150, alpha))
+	}
+}
+
+// ─────────────────────────────────────────────
+// Elementos orbitais osculantes
+
+// orbiter é o estado de um corpo relativo ao seu primário, de onde saem os
+// elementos osculantes. Center é a posição do primário na cena.
+type orbiter struct {
+	Name, Primary string
+	R, V          astro.Vec3 // relativos ao primário
+	Mu            float64
+	Center        rl.Vector3
+	Unit          string                      // unidade de distância
+	Days          float64                     // dias por unidade de tempo
+	ToScene       func(astro.Vec3) rl.Vector3 // posição relativa → cena heliocêntrica
+}
+
+// orbiters lista os corpos da cena com seus estados atuais. Planetas e luas
+// seguem o modelo de ângulos (velocidade ω × r), com o μ total ω²r³ da própria
+// órbita, e por isso saem circulares com a = OrbitRadius; a nave, os troianos
+// e o cometa seguem as próprias velocidades, com o μ que os integra.
+func (sim *Simulation) orbiters() []orbiter {
+	scene := func(center rl.Vector3) func(astro.Vec3) rl.Vector3 {
+		return func(r astro.Vec3) rl.Vector3 { return rl.Vector3Add(center, eclipticToScene(r)) }
+	}
+	var out []orbiter
+	add := func(name, primary string, r, v astro.Vec3, mu float64, center rl.Vector3) {
+		out = append(out, orbiter{Name: name, Primary: primary, R: r, V: v, Mu: mu, Center: center,
+			Unit: "px", Days: orbitalDaysPerFrame, ToScene: scene(center)})
+	}
+	for _, p := range sim.Planets {
+		r, v := planetState(p, 0)
+		add(p.Name, "Sol", r, v, astro.CircularMu(p.OrbitSpeed, p.OrbitRadius), rl.Vector3{})
+		for i, m := range p.Moons {
+			add(moonName(p, i), p.Name, m.Offset(), m.Velocity(), astro.CircularMu(m.OrbitSpeed, m.OrbitRadius), p.Position())
+		}
+	}
+	if c := sim.Craft; c != nil {
+		// Dentro de uma esfera de influência, o primário é o planeta
+		name, r, v, mu, center := "Sol", c.Position, c.Velocity, sceneSunMu, rl.Vector3{}
+		for _, p := range sim.Planets {
+			pos, vel := planetState(p, 0)
+			if c.Position.Sub(pos).Norm() < sceneSOI(p) {
+				name, r, v, mu, center = p.Name, c.Position.Sub(pos), c.Velocity.Sub(vel), sceneGM(p), p.Position()
+				break
+			}
+		}
+		add(c.Name, name, r, v, mu, center)
+	}
+	if len(sim.Trojans) > 0 {
+		tr := sim.Trojans[0]
+		muSun, _ := planetMu(sim.TrojanHost)
+		add("Troiano 1", "Sol", tr.Position, tr.Velocity, muSun, rl.Vector3{})
+	}
+	cometVel := astro.Vec3{X: sim.Comet.Speed * math.Cos(sim.Comet.Angle), Y: sim.Comet.Speed * math.Sin(sim.Comet.Angle)}
+	add("Cometa", "Sol", sceneToEcliptic(sim.Comet.Position), cometVel, sceneSunMu, rl.Vector3{})
+	if p := sim.TransferProbe; p != nil {
+		dt := math.Max(0, math.Min(sim.JD, p.Cell.Arrival)-p.Cell.Departure)
+		r, v := astro.Propagate(p.R1, p.V1, dt, astro.GM(astro.Sun))
+		out = append(out, orbiter{Name: "Sonda de transferência", Primary: "Sol", R: r, V: v, Mu: astro.GM(astro.Sun),
+			Unit: "UA", Days: 1, ToScene: sim.auToScene})
+	}
+	return out
+}
+
+// selectedOrbiter devolve o corpo escolhido, se ele ainda existir.
+func (sim *Simulation) selectedOrbiter() (orbiter, bool) {
+	if sim.Selected == "" {
+		return orbiter{}, false
+	}
+	for _, o := range sim.orbiters() {
+		if o.Name == sim.Selected {
+			return o, true
+		}
+	}
+	return orbiter{}, false
+}
+
+// NextSelection escolhe o próximo corpo da lista; depois do último, nenhum.
+func (sim *Simulation) NextSelection() {
+	list := sim.orbiters()
+	for i, o := range list {
+		if o.Name == sim.Selected {
+			if i+1 < len(list) {
+				sim.Selected = list[i+1].Name
+			} else {
+				sim.Selected = ""
+			}
+			return
+		}
+	}
+	sim.Selected = list[0].Name
+}
+
+// drawOsculatingOrbit desenha a órbita kepleriana osculante do corpo
+// escolhido; comparada ao movimento real, mostra perturbações e precessão.
+func (sim *Simulation) drawOsculatingOrbit() {
+	o, ok := sim.selectedOrbiter()
+	if !ok {
+		return
+	}
+	el := astro.ElementsFromState(o.R, o.V, o.Mu)
+	col := rl.NewColor(255, 255, 120, 180)
+	// Órbitas abertas: só o trecho com r finito, até 90% da assíntota
+	from, to := -math.Pi, math.Pi
+	if el.E >= 1 {
+		limit := 0.9 * math.Acos(-1/el.E)
+		from, to = -limit, limit
+	}
+	const samples = 180
+	prev := sim.toFrame(o.ToScene(el.PositionAt(from)))
+	for i := 1; i <= samples; i++ {
+		next := sim.toFrame(o.ToScene(el.PositionAt(from + (to-from)*float64(i)/samples)))
+		rl.DrawLine3D(prev, next, col)
+		prev = next
+	}
+	rl.DrawSphereWires(sim.toFrame(o.ToScene(o.R)), 4, 4, 8, col)
+}
+
+// DrawOrbitalElements escreve os elementos osculantes do corpo escolhido.
+// Deve ser chamada fora do modo 3D.
+func (sim *Simulation) DrawOrbitalElements(x, y int32) {
+	o, ok := sim.selectedOrbiter()
+	if !ok {
+		return
+	}
+	el := astro.ElementsFromState(o.R, o.V, o.Mu)
+	deg := 180 / math.Pi
+	distance := func(v float64) string {
+		if math.IsInf(v, 1) {
+			return "infinito"
+		}
+		return fmt.Sprintf("%.2f %s", v, o.Unit)
+	}
+	period := "infinito"
+	if !math.IsInf(el.Period, 1) {
+		period = fmt.Sprintf("%.1f dias", el.Period*o.Days)
+	}
+	lines := []string{
+		o.Name + " em torno de " + o.Primary,
+		"a = " + distance(el.A),
+		fmt.Sprintf("e = %.4f", el.E),
+		fmt.Sprintf("i = %.2f°", el.I*deg),
+		fmt.Sprintf("Nodo ascendente = %.2f°", el.Node*deg),
+		fmt.Sprintf("Arg. da periapse = %.2f°", el.ArgPeri*deg),
+		fmt.Sprintf("Anomalia verdadeira = %.2f°", el.TrueAnomaly*deg),
+		"Período = " + period,
+		"Periapse = " + distance(el.Periapsis),
+		"Apoapse = " + distance(el.Apoapsis),
+	}
+	rl.DrawRectangle(x-8, y-8, 320, int32(len(lines))*22+12, rl.NewColor(0, 0, 0, 150))
+	for i, line := range lines {
+		rl.DrawText(line, x, y+int32(i)*22, 18, rl.White)
+	}
+}
+
+// ─────────────────────────────────────────────
+// Porkchop e sonda de transferência (problema de Lambert)
+
+// TransferProbe é uma sonda numa transferência direta entre dois planetas,
+// calculada em unidades reais (UA, dias) e desenhada na escala da cena.
+type TransferProbe struct {
+	Cell   astro.PorkchopCell
+	R1, V1 astro.Vec3 // estado heliocêntrico na partida
+}
+
+// Position devolve a posição heliocêntrica (UA) da sonda na data jd; antes da
+// partida ela espera no planeta de origem e depois da chegada fica no destino.
+func (p *TransferProbe) Position(jd float64) astro.Vec3 {
+	dt := math.Max(0, math.Min(jd, p.Cell.Arrival)-p.Cell.Departure)
+	r, _ := astro.Propagate(p.R1, p.V1, dt, astro.GM(astro.Sun))
+	return r
+}
+
+// LoadPorkchop lê o CSV gravado pelo comando porkchop.
+func (sim *Simulation) LoadPorkchop(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	grid, err := astro.ReadPorkchopCSV(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if len(grid) == 0 || len(grid[0]) == 0 {
+		return fmt.Errorf("%s: porkchop vazio", path)
+	}
+	sim.Porkchop = grid
+	sim.ShowPorkchop = true
+	return nil
+}
+
+// LoadTransfer lança a sonda de uma célula do porkchop: o relógio volta para a
+// data de partida e os planetas passam a seguir as efemérides, para que o
+// encontro aconteça na cena.
+func (sim *Simulation) LoadTransfer(cell astro.PorkchopCell) error {
+	r1, v1, err := astro.TransferOrbit(cell.From, cell.To, cell.Departure, cell.Arrival)
+	if err != nil {
+		return err
+	}
+	sim.TransferProbe = &TransferProbe{Cell: cell, R1: r1, V1: v1}
+	sim.JD = cell.Departure
+	sim.Ephemeris = true
+	return nil
+}
+
+// sceneRadius converte uma distância ao Sol (UA) para pixels da cena,
+// interpolando entre os semieixos maiores reais e os raios orbitais dos
+// planetas da simulação.
+func (sim *Simulation) sceneRadius(au float64) float64 {
+	prevAU, prevPx := 0.0, 0.0
+	for _, p := range sim.Planets {
+		b, ok := astro.BodyByName(p.Name)
+		if !ok {
+			continue
+		}
+		a := astro.SemiMajorAxis(b)
+		if au <= a {
+			return prevPx + (au-prevAU)*(p.OrbitRadius-prevPx)/(a-prevAU)
+		}
+		prevAU, prevPx = a, p.OrbitRadius
+	}
+	return prevPx * au / prevAU
+}
+
+// auToScene leva uma posição heliocêntrica eclíptica (UA) para a cena,
+// mantendo a direção e convertendo a distância com sceneRadius.
+func (sim *Simulation) auToScene(r astro.Vec3) rl.Vector3 {
+	n := r.Norm()
+	if n == 0 {
+		return rl.Vector3{}
+	}
+	return eclipticToScene(r.Scale(sim.sceneRadius(n) / n))
+}
+
+// drawTransferProbe desenha o arco da transferência escolhida no porkchop, o
+// ponto de encontro e a sonda.
+func (sim *Simulation) drawTransferProbe() {
+	p := sim.TransferProbe
+	if p == nil {
+		return
+	}
+	const samples = 200
+	col := rl.NewColor(255, 150, 230, 170)
+	span := p.Cell.Arrival - p.Cell.Departure
+	prev := sim.toFrame(sim.auToScene(p.R1))
+	for i := 1; i <= samples; i++ {
+		next := sim.toFrame(sim.auToScene(p.Position(p.Cell.Departure + span*float64(i)/samples)))
+		rl.DrawLine3D(prev, next, col)
+		prev = next
+	}
+	rl.DrawSphereWires(prev, 3, 4, 6, col)
+	drawSphere(sim.toFrame(sim.auToScene(p.Position(sim.JD))), 2, rl.NewColor(255, 200, 240, 255))
+}
+
+// porkchopColor pinta o C3 de azul (baixo) a vermelho (alto).
+func porkchopColor(c astro.PorkchopCell, c3Max float64) rl.Color {
+	if !c.Valid {
+		return rl.NewColor(60, 60, 60, 200)
+	}
+	t := float32(math.Min(c.C3/c3Max, 1))
+	return rl.ColorLerp(rl.NewColor(40, 60, 200, 220), rl.NewColor(220, 50, 40, 220), t)
+}
+
+// porkchopPanel é a área do painel do porkchop na tela.
+func porkchopPanel(screenWidth int32) rl.Rectangle {
+	return rl.NewRectangle(float32(screenWidth)-340, 20, 320, 320)
+}
+
+// porkchopCellAt devolve a célula sob o ponto da tela, se houver. A partida
+// cresce para a direita e a chegada para cima.
+func (sim *Simulation) porkchopCellAt(panel rl.Rectangle, pt rl.Vector2) (astro.PorkchopCell, bool) {
+	if !rl.CheckCollisionPointRec(pt, panel) {
+		return astro.PorkchopCell{}, false
+	}
+	cols, rows := len(sim.Porkchop), len(sim.Porkchop[0])
+	i := int((pt.X - panel.X) / panel.Width * float32(cols))
+	j := int((panel.Y + panel.Height - pt.Y) / panel.Height * float32(rows))
+	i, j = min(i, cols-1), min(j, rows-1)
+	if j >= len(sim.Porkchop[i]) {
+		return astro.PorkchopCell{}, false
+	}
+	return sim.Porkchop[i][j], true
+}
+
+// DrawPorkchop desenha o painel do porkchop e a célula sob o mouse. Deve ser
+// chamada fora do modo 3D.
+func (sim *Simulation) DrawPorkchop(screenWidth int32) {
+	if !sim.ShowPorkchop || sim.Porkchop == nil {
+		return
+	}
+	panel := porkchopPanel(screenWidth)
+	cols, rows := len(sim.Porkchop), len(sim.Porkchop[0])
+	w, h := panel.Width/float32(cols), panel.Height/float32(rows)
+	for i, row := range sim.Porkchop {
+		for j, c := range row {
+			x := panel.X + float32(i)*w
+			y := panel.Y + panel.Height - float32(j+1)*h
+			rl.DrawRectangleRec(rl.NewRectangle(x, y, w+1, h+1), porkchopColor(c, 50))
+		}
+	}
+	rl.DrawRectangleLinesEx(panel, 1, rl.White)
+	first := sim.Porkchop[0][0]
+	rl.DrawText(first.From.String()+" -> "+first.To.String()+": partida (x) x chegada (y)",
+		int32(panel.X), int32(panel.Y+panel.Height)+6, 16, rl.White)
+	if c, ok := sim.porkchopCellAt(panel, rl.GetMousePosition()); ok {
+		text := fmt.Sprintf("%s -> %s", astro.TimeFromJulian(c.Departure).Format("2006-01-02"),
+			astro.TimeFromJulian(c.Arrival).Format("2006-01-02"))
+		if c.Valid {
+			text += fmt.Sprintf(" | C3 %.1f km²/s² | dv %.2f km/s", c.C3, c.DeltaV())
+		}
+		rl.DrawText(text, int32(panel.X), int32(panel.Y+panel.Height)+26, 16, rl.White)
+	}
+}
+
+// ─────────────────────────────────────────────
+// Tabelas de eventos: eclipses, trânsitos e fenômenos planetários
+
+// Tamanho das tabelas de eventos: número máximo de linhas e altura de cada uma.
+const (
+	eventRows      = 20
+	eventRowHeight = 20
+)
+
+// EventRow é uma linha de uma tabela de eventos.
+type EventRow struct {
+	Cells  []string
+	JD     float64 // instante para onde o relógio salta
+	Detail string  // mostrado sob a tabela com o mouse sobre a linha
+}
+
+// EventTable é uma tabela de eventos datados desenhada no centro da tela; um
+// clique numa linha leva o relógio ao instante do evento, com as efemérides
+// ligadas e o relógio a TimeScale dias por frame.
+type EventTable struct {
+	Title     string
+	Columns   []string
+	Offsets   []int32 // posição x de cada coluna
+	Rows      []EventRow
+	TimeScale float64
+}
+
+// ToggleEvents abre a tabela montada por build a partir da data do relógio
+// ou a fecha, se ela já estiver aberta.
+func (sim *Simulation) ToggleEvents(title string, build func(jd float64) *EventTable) {
+	if sim.Events != nil && sim.Events.Title == title {
+		sim.Events = nil
+		return
+	}
+	sim.Events = build(sim.JD)
+	sim.Events.Title = title
+}
+
+// JumpToEvent leva o relógio ao instante da linha.
+func (sim *Simulation) JumpToEvent(row EventRow) {
+	sim.JD = row.JD
+	sim.TimeScale = sim.Events.TimeScale
+	sim.Ephemeris = true
+}
+
+// formatJD formata uma data juliana em UTC com o layout de time.
+func formatJD(jd float64, layout string) string {
+	return astro.TimeFromJulian(jd).Format(layout)
+}
+
+// eclipseSearchDays é o intervalo varrido pela tabela de eclipses.
+const eclipseSearchDays = 5 * 365.25
+
+// eclipseTable lista os eclipses e trânsitos dos próximos anos. O salto vai
+// ao máximo, com o relógio a um minuto por frame para que o alinhamento
+// possa ser visto.
+func eclipseTable(jd float64) *EventTable {
+	events := append(astro.FindEclipses(jd, jd+eclipseSearchDays), astro.FindTransits(jd, jd+eclipseSearchDays)...)
+	sort.Slice(events, func(i, j int) bool { return events[i].Maximum < events[j].Maximum })
+	table := &EventTable{
+		Columns:   []string{"Evento", "Tipo", "Início (UTC)", "Máximo", "Fim"},
+		Offsets:   []int32{0, 200, 300, 440, 580},
+		TimeScale: 1.0 / 1440,
+	}
+	for _, e := range events[:min(len(events), eventRows)] {
+		detail := "Contatos:"
+		for _, c := range e.Contacts {
+			detail += fmt.Sprintf(" %s %s", c.Name, formatJD(c.JD, "15:04"))
+		}
+		table.Rows = append(table.Rows, EventRow{
+			Cells: []string{e.Kind.String(), e.Type.String(), formatJD(e.Start(), "2006-01-02 15:04"),
+				formatJD(e.Maximum, "15:04"), formatJD(e.End(), "01-02 15:04")},
+			JD:     e.Maximum,
+			Detail: detail,
+		})
+	}
+	return table
+}
+
+// phenomenaSearchDays é o intervalo varrido pela tabela de fenômenos.
+const phenomenaSearchDays = 365.25
+
+// phenomenaTable lista as conjunções, oposições, elongações e estações dos
+// planetas da simulação vistas da Terra. O relógio anda uma hora por frame
+// depois do salto.
+func (sim *Simulation) phenomenaTable(jd float64) *EventTable {
+	var events []astro.Phenomenon
+	for _, p := range sim.Planets {
+		if b, ok := astro.BodyByName(p.Name); ok && b != astro.Earth {
+			events = append(events, astro.FindPhenomena(b, jd, jd+phenomenaSearchDays)...)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].JD < events[j].JD })
+	table := &EventTable{
+		Columns:   []string{"Data (UTC)", "Planeta", "Evento", "Elongação"},
+		Offsets:   []int32{0, 160, 280, 520},
+		TimeScale: 1.0 / 24,
+	}
+	for _, e := range events[:min(len(events), eventRows)] {
+		table.Rows = append(table.Rows, EventRow{
+			Cells: []string{formatJD(e.JD, "2006-01-02 15:04"), e.Body.String(), e.Kind.String(),
+				fmt.Sprintf("%.1f°", e.Elongation*180/math.Pi)},
+			JD:     e.JD,
+			Detail: fmt.Sprintf("Longitude eclíptica geocêntrica %.2f°", e.Longitude*180/math.Pi),
+		})
+	}
+	return table
+}
+
+// eventPanel é a área da tabela aberta na tela, uma linha por evento abaixo
+// do cabeçalho.
+func (sim *Simulation) eventPanel(screenWidth int32) rl.Rectangle {
+	rows := float32(len(sim.Events.Rows) + 1)
+	return rl.NewRectangle(float32(screenWidth)/2-340, 290, 680, rows*eventRowHeight+8)
+}
+
+// eventAt devolve a linha sob o ponto da tela, se houver.
+func (sim *Simulation) eventAt(panel rl.Rectangle, pt rl.Vector2) (EventRow, bool) {
+	if !rl.CheckCollisionPointRec(pt, panel) {
+		return EventRow{}, false
+	}
+	i := int((pt.Y-panel.Y-4)/eventRowHeight) - 1
+	if i < 0 || i >= len(sim.Events.Rows) {
+		return EventRow{}, false
+	}
+	return sim.Events.Rows[i], true
+}
+
+// DrawEvents desenha a tabela aberta e, abaixo dela, o detalhe da linha sob
+// o mouse. Deve ser chamada fora do modo 3D.
+func (sim *Simulation) DrawEvents(screenWidth int32) {
+	table := sim.Events
+	if table == nil {
+		return
+	}
+	panel := sim.eventPanel(screenWidth)
+	rl.DrawRectangleRec(panel, rl.NewColor(0, 0, 0, 190))
+	rl.DrawRectangleLinesEx(panel, 1, rl.White)
+	x, y := int32(panel.X)+8, int32(panel.Y)+4
+	rl.DrawText(table.Title, int32(panel.X), int32(panel.Y)-22, 18, rl.White)
+	for i, title := range table.Columns {
+		rl.DrawText(title, x+table.Offsets[i], y, 16, rl.Gray)
+	}
+	if len(table.Rows) == 0 {
+		rl.DrawText("Nenhum evento no intervalo", x, y+eventRowHeight, 16, rl.White)
+	}
+	hover, hovered := sim.eventAt(panel, rl.GetMousePosition())
+	for i, row := range table.Rows {
+		col := rl.White
+		if hovered && row.JD == hover.JD {
+			col = rl.Yellow
+		}
+		for k, text := range row.Cells {
+			rl.DrawText(text, x+table.Offsets[k], y+int32(i+1)*eventRowHeight, 16, col)
+		}
+	}
+	if hovered {
+		rl.DrawText(hover.Detail, int32(panel.X), int32(panel.Y+panel.Height)+6, 16, rl.White)
+	}
+	rl.DrawText("Clique numa linha para ir ao evento", int32(panel.X), int32(panel.Y+panel.Height)+26, 16, rl.Gray)
+}
+
+// ─────────────────────────────────────────────
+// Planejador de transferências (Hohmann e bi-elíptica)
+
+// biEllipticFactor é o raio intermediário da bi-elíptica, em múltiplos do
+// maior dos dois raios orbitais.
+const biEllipticFactor = 1.6
+
+// TransferPlan é uma transferência entre as órbitas de dois planetas. Os
+// números do HUD usam as órbitas reais (semieixo maior, GM do Sol); o arco e a
+// sonda da cena usam as órbitas circulares da simulação, em pixels e frames.
+type TransferPlan struct {
+	From, To   *Planet
+	BiElliptic bool
+
+	Real       astro.Transfer // UA e dias
+	RealWindow float64        // data juliana da próxima janela de lançamento
+
+	scene       astro.Transfer
+	sceneMu     float64 // μ do Sol na escala da cena, tirado da órbita de origem
+	launched    bool
+	launchAngle float64
+	elapsed     float64 // frames desde o lançamento
+}
+
+// NewTransferPlan cria o plano Terra → Marte (ou entre os dois primeiros planetas).
+func (sim *Simulation) NewTransferPlan() *TransferPlan {
+	from, to := sim.planetByBody(astro.Earth), sim.planetByBody(astro.Mars)
+	if from == nil || to == nil {
+		from, to = sim.Planets[0], sim.Planets[1]
+	}
+	plan := &TransferPlan{From: from, To: to}
+	plan.Recalculate()
+	return plan
+}
+
+// Recalculate refaz as duas versões da manobra depois de trocar origem,
+// destino ou tipo, e cancela a sonda em voo.
+func (plan *TransferPlan) Recalculate() {
+	plan.launched = false
+	plan.Real = astro.Transfer{}
+	if b1, ok := astro.BodyByName(plan.From.Name); ok {
+		if b2, ok := astro.BodyByName(plan.To.Name); ok {
+			plan.Real = plan.maneuver(astro.GM(astro.Sun), astro.SemiMajorAxis(b1), astro.SemiMajorAxis(b2))
+		}
+	}
+	r1 := plan.From.OrbitRadius
+	plan.sceneMu = plan.From.OrbitSpeed * plan.From.OrbitSpeed * r1 * r1 * r1
+	plan.scene = plan.maneuver(plan.sceneMu, r1, plan.To.OrbitRadius)
+}
+
+func (plan *TransferPlan) maneuver(mu, r1, r2 float64) astro.Transfer {
+	if plan.BiElliptic {
+		return astro.BiElliptic(mu, r1, r2, biEllipticFactor*math.Max(r1, r2))
+	}
+	return astro.Hohmann(mu, r1, r2)
+}
+
+// cyclePlanet devolve o planeta seguinte a p, pulando skip.
+func (sim *Simulation) cyclePlanet(p, skip *Planet) *Planet {
+	for i, q := range sim.Planets {
+		if q != p {
+			continue
+		}
+		next := sim.Planets[(i+1)%len(sim.Planets)]
+		if next == skip {
+			next = sim.Planets[(i+2)%len(sim.Planets)]
+		}
+		return next
+	}
+	return p
+}
+
+// sceneWait devolve quantos frames faltam para a próxima janela na cena.
+func (plan *TransferPlan) sceneWait() float64 {
+	phase := plan.To.Angle - plan.From.Angle
+	return astro.WaitForPhase(phase, plan.scene.RequiredPhase(plan.To.OrbitSpeed), plan.To.OrbitSpeed-plan.From.OrbitSpeed)
+}
+
+// updateTransfer atualiza a janela real e lança a sonda da cena quando a fase
+// entre os planetas da simulação chega ao valor certo.
+func (sim *Simulation) updateTransfer() {
+	plan := sim.Transfer
+	if plan == nil {
+		return
+	}
+	if b1, ok := astro.BodyByName(plan.From.Name); ok {
+		if b2, ok := astro.BodyByName(plan.To.Name); ok {
+			plan.RealWindow = astro.NextLaunchWindow(b1, b2, plan.Real, sim.JD)
+		}
+	}
+	if plan.launched {
+		plan.elapsed++
+		if plan.elapsed >= plan.scene.TimeOfFlight {
+			plan.launched = false
+		}
+		return
+	}
+	if plan.sceneWait() < 1 {
+		plan.launched = true
+		plan.launchAngle = plan.From.Angle
+		plan.elapsed = 0
+	}
+}
+
+// drawTransfer desenha o arco da transferência (o próximo, se a sonda ainda
+// não partiu) e a sonda em voo.
+func (sim *Simulation) drawTransfer() {
+	plan := sim.Transfer
+	if plan == nil {
+		return
+	}
+	start := plan.launchAngle
+	if !plan.launched {
+		start = plan.From.Angle + plan.From.OrbitSpeed*plan.sceneWait()
+	}
+	at := func(t float64) rl.Vector3 {
+		r, a := plan.scene.PositionAt(plan.sceneMu, t)
+		a += start
+		return sim.toFrame(rl.NewVector3(float32(r*math.Cos(a)), 0, float32(r*math.Sin(a))))
+	}
+
+	const samples = 160
+	arcColor := rl.NewColor(80, 220, 255, 160)
+	if !plan.launched {
+		arcColor.A = 70
+	}
+	prev := at(0)
+	for i := 1; i <= samples; i++ {
+		next := at(plan.scene.TimeOfFlight * float64(i) / samples)
+		rl.DrawLine3D(prev, next, arcColor)
+		prev = next
+	}
+	if plan.launched {
+		drawSphere(at(plan.elapsed), 2, rl.NewColor(180, 255, 220, 255))
+	}
+}
+
+// Summary descreve a manobra real para o HUD.
+func (plan *TransferPlan) Summary() string {
+	kind := "Hohmann"
+	if plan.BiElliptic {
+		kind = "bi-elíptica"
+	}
+	burns := ""
+	for i, dv := range plan.Real.Burns {
+		if i > 0 {
+			burns += " + "
+		}
+		burns += fmt.Sprintf("%.2f", dv*astro.KmPerSecond)
+	}
+	return fmt.Sprintf("%s -> %s (%s): dv %.2f km/s (%s) | voo %.0f dias | janela %s",
+		plan.From.Name, plan.To.Name, kind, plan.Real.DeltaV*astro.KmPerSecond, burns,
+		plan.Real.TimeOfFlight, astro.TimeFromJulian(plan.RealWindow).Format("2006-01-02"))
+}
+
+// ─────────────────────────────────────────────
+// Pontos de Lagrange
+
+// lagrangePairs são os pares primário–secundário alternados com a tecla L.
+var lagrangePairs = [][2]astro.Body{
+	{astro.Sun, astro.Earth},
+	{astro.Earth, astro.Moon},
+	{astro.Sun, astro.Jupiter},
+}
+
+// sceneBody devolve a posição, na cena heliocêntrica, de um corpo da
+// simulação: o Sol, um planeta ou a Lua (primeira lua da Terra).
+func (sim *Simulation) sceneBody(b astro.Body) (rl.Vector3, bool) {
+	switch b {
+	case astro.Sun:
+		return rl.Vector3{}, true
+	case astro.Moon:
+		earth := sim.planetByBody(astro.Earth)
+		if earth == nil || len(earth.Moons) == 0 {
+			return rl.Vector3{}, false
+		}
+		return earth.Moons[0].Position(earth.Position()), true
+	}
+	if p := sim.planetByBody(b); p != nil {
+		return p.Position(), true
+	}
+	return rl.Vector3{}, false
+}
+
+// LagrangePoints devolve L1–L5 do par escolhido, na cena heliocêntrica. As
+// órbitas da simulação são circulares e diretas, no plano da eclíptica.
+func (sim *Simulation) LagrangePoints() ([5]rl.Vector3, bool) {
+	var out [5]rl.Vector3
+	if sim.LagrangePair == 0 {
+		return out, false
+	}
+	pair := lagrangePairs[sim.LagrangePair-1]
+	r1, ok1 := sim.sceneBody(pair[0])
+	r2, ok2 := sim.sceneBody(pair[1])
+	if !ok1 || !ok2 {
+		return out, false
+	}
+	points := astro.PairLagrangePoints(sceneToEcliptic(r1), sceneToEcliptic(r2),
+		astro.Vec3{Z: 1}, astro.MassRatio(pair[0], pair[1]))
+	for i, p := range points {
+		out[i] = eclipticToScene(p)
+	}
+	return out, true
+}
+
+// LagrangePairName descreve o par escolhido para o HUD.
+func (sim *Simulation) LagrangePairName() string {
+	if sim.LagrangePair == 0 {
+		return "desligado"
+	}
+	pair := lagrangePairs[sim.LagrangePair-1]
+	return pair[0].String() + "–" + pair[1].String()
+}
+
+// drawLagrangePoints marca L1–L5 com pequenos octaedros de arame.
+func (sim *Simulation) drawLagrangePoints() {
+	points, ok := sim.LagrangePoints()
+	if !ok {
+		return
+	}
+	col := rl.NewColor(120, 255, 160, 220)
+	for _, p := range points {
+		p = sim.toFrame(p)
+		rl.DrawSphereWires(p, 2.5, 2, 4, col)
+		rl.DrawLine3D(rl.NewVector3(p.X, p.Y-4, p.Z), rl.NewVector3(p.X, p.Y+4, p.Z), col)
+	}
+}
+
+// DrawLagrangeLabels escreve L1–L5 ao lado dos marcadores.
+// Deve ser chamada fora do modo 3D, depois de rl.EndMode3D.
+func (sim *Simulation) DrawLagrangeLabels(camera rl.Camera3D) {
+	points, ok := sim.LagrangePoints()
+	if !ok {
+		return
+	}
+	forward := rl.Vector3Subtract(camera.Target, camera.Position)
+	for i, p := range points {
+		p = sim.toFrame(p)
+		if rl.Vector3DotProduct(rl.Vector3Subtract(p, camera.Position), forward) <= 0 {
+			continue
+		}
+		pos := rl.GetWorldToScreen(p, camera)
+		rl.DrawText(astro.LagrangeNames[i], int32(pos.X)+6, int32(pos.Y)-6, 14, rl.NewColor(120, 255, 160, 255))
+	}
+}
+
+// ─────────────────────────────────────────────
+// Referenciais da cena
+
+// frameChoices são os referenciais percorridos com a tecla F.
+var frameChoices = []astro.Frame{
+	astro.HeliocentricFrame,
+	astro.BarycentricFrame,
+	astro.GeocentricFrame,
+	astro.CenteredOn(astro.Mercury),
+	astro.CenteredOn(astro.Venus),
+	astro.CenteredOn(astro.Earth),
+	astro.CenteredOn(astro.Mars),
+	astro.CenteredOn(astro.Jupiter),
+	astro.CenteredOn(astro.Saturn),
+	astro.CenteredOn(astro.Uranus),
+	astro.CenteredOn(astro.Neptune),
+	astro.CenteredOn(astro.Pluto),
+}
+
+// NextFrame avança para o próximo referencial da lista (e sai do girante).
+func (sim *Simulation) NextFrame() {
+	sim.resetTrails()
+	if sim.RotatingPlanet != nil {
+		sim.RotatingPlanet = nil
+		return
+	}
+	for i, f := range frameChoices {
+		if f == sim.Frame {
+			sim.Frame = frameChoices[(i+1)%len(frameChoices)]
+			return
+		}
+	}
+	sim.Frame = frameChoices[0]
+}
+
+// sceneToEcliptic é a inversa de eclipticToScene.
+func sceneToEcliptic(p rl.Vector3) astro.Vec3 {
+	return astro.Vec3{X: float64(p.X), Y: float64(p.Z), Z: float64(-p.Y)}
+}
+
+// planetByBody procura o planeta da simulação correspondente ao corpo.
+func (sim *Simulation) planetByBody(b astro.Body) *Planet {
+	for _, p := range sim.Planets {
+		if pb, ok := astro.BodyByName(p.Name); ok && pb == b {
+			return p
+		}
+	}
+	return nil
+}
+
+// updateFrameOrigin calcula onde fica, na cena heliocêntrica, a origem do
+// referencial escolhido. O baricentro usa as massas reais dos planetas; o
+// referencial girante fica centrado no Sol.
+func (sim *Simulation) updateFrameOrigin() {
+	var center astro.Body
+	if sim.RotatingPlanet != nil {
+		sim.frameOrigin = rl.Vector3{}
+		return
+	}
+	switch sim.Frame.Kind {
+	case astro.Barycentric:
+		var sum astro.Vec3
+		total := astro.GM(astro.Sun)
+		for _, p := range sim.Planets {
+			if b, ok := astro.BodyByName(p.Name); ok {
+				sum = sum.Add(sceneToEcliptic(p.Position()).Scale(astro.GM(b)))
+				total += astro.GM(b)
+			}
+		}
+		sim.frameOrigin = eclipticToScene(sum.Scale(1 / total))
+		return
+	case astro.GeocentricEquatorial:
+		center = astro.Earth
+	case astro.BodyCentered:
+		center = sim.Frame.Center
+	default:
+		sim.frameOrigin = rl.Vector3{}
+		return
+	}
+	if p := sim.planetByBody(center); p != nil {
+		sim.frameOrigin = p.Position()
+	} else {
+		sim.frameOrigin = rl.Vector3{}
+	}
+}
+
+// toFrame converte uma posição da cena heliocêntrica para o referencial escolhido.
+func (sim *Simulation) toFrame(p rl.Vector3) rl.Vector3 {
+	return sim.orientSky(rl.Vector3Subtract(p, sim.frameOrigin))
+}
+
+// orientSky apenas gira uma direção para os eixos do referencial; usada para a
+// esfera celeste, que não se desloca com a origem.
+func (sim *Simulation) orientSky(p rl.Vector3) rl.Vector3 {
+	if sim.RotatingPlanet != nil {
+		return corotate(p, sim.RotatingPlanet.Angle)
+	}
+	if sim.Frame.Kind != astro.GeocentricEquatorial {
+		return p
+	}
+	return eclipticToScene(sim.Frame.Orient(sceneToEcliptic(p)))
+}
+
+// Desenha a cena 3D usando as funções nativas (esferas, modelo do anel e efeitos)
+func (sim *Simulation) Draw3D(ringModel rl.Model) {
+	// Todas as posições passam pelo referencial escolhido
+	sim.updateFrameOrigin()
+
+	// Desenha o Sol
+	drawSphere(sim.toFrame(rl.NewVector3(0, 0, 0)), sim.SunRadius, rl.Yellow)
+
+	// OBS.: As órbitas dos planetas foram removidas conforme solicitado.
+
+	// Desenha os planetas e suas luas
+	for _, p := range sim.Planets {
+		planetPos := p.Position()
+		drawSphere(sim.toFrame(planetPos), p.Radius, p.Color)
+
+		// Se for Saturn, desenha os anéis
+		if p.Name == "Saturn" {
+			rl.DrawModelEx(ringModel, sim.toFrame(planetPos), rl.NewVector3(1, 0, 0), 25, rl.NewVector3(p.Radius*3, 1, p.Radius*3), rl.LightGray)
+		}
+		// Desenha as luas
+		for _, m := range p.Moons {
+			drawSphere(sim.toFrame(m.Position(planetPos)), m.Radius, m.Color)
+		}
+	}
+
+	// Desenha os asteroides
+	for _, a := range sim.Asteroids {
+		ax := float32(a.OrbitRadius * math.Cos(a.Angle))
+		az := float32(a.OrbitRadius * math.Sin(a.Angle))
+		asteroidPos := rl.NewVector3(ax, 0, az)
+		drawSphere(sim.toFrame(asteroidPos), a.Radius, rl.Gray)
+	}
+
+	// Desenha os troianos e os pontos de Lagrange
+	sim.drawTrojans()
+	sim.drawLagrangePoints()
+	sim.drawTransfer()
+	sim.drawCraft()
+	sim.drawFlyby()
+	sim.drawTransferProbe()
+	sim.drawOsculatingOrbit()
+	sim.drawSpheres()
+	sim.drawPicked()
+
+	// Desenha os rastros das órbitas
+	sim.drawTrails()
+
+	// Desenha o rastro do cometa (meteoro): esferas e linhas com alfa
+	// decrescente, para um efeito contínuo
+	sim.Comet.Tail.Segments(sim.Time, func(a, b rl.Vector3, age float64) {
+		col := rl.NewColor(255, 255, 255, uint8(200*(1-age)))
+		drawSphere(sim.toFrame(a), 2, col)
+		rl.DrawLine3D(sim.toFrame(a), sim.toFrame(b), col)
+	})
+
+	// Desenha o cometa (meteoro)
+	drawSphere(sim.toFrame(sim.Comet.Position), 4, rl.White)
+
+	// Desenha as estrelas cintilantes
+	for _, star := range sim.Stars {
+		brightness := float32(128 + 127*math.Sin(star.Phase))
+		if brightness < 0 {
+			brightness = 0
+		} else if brightness > 255 {
+			brightness = 255
+		}
+		col := star.Color
+		if star.Twinkle {
+			col.A = uint8(brightness)
+		}
+		drawSphere(sim.orientSky(star.Position), star.Size, col)
+	}
+
+	// Figuras das constelações
+	if sim.ShowConstellations {
+		lineColor := rl.NewColor(90, 140, 220, 160)
+		for _, c := range sim.Constellations {
+			for _, seg := range c.Segments {
+				rl.DrawLine3D(sim.orientSky(seg[0]), sim.orientSky(seg[1]), lineColor)
+			}
+		}
+	}
+
+	// Se uma explosão estiver ativa, desenha o efeito de explosão
+	if sim.ExplosionActive {
+		maxExplosionRadius := float32(30)
+		explosionRadius := float32(sim.ExplosionTime/sim.ExplosionDuration) * maxExplosionRadius
+		alpha := uint8(255 * (1 - float32(sim.ExplosionTime)/float32(sim.ExplosionDuration)))
+		explosionColor := rl.NewColor(255, 200, 0, alpha)
+		rl.DrawSphere(sim.toFrame(sim.ExplosionPosition), explosionRadius, explosionColor)
+	}
+}
+
+// DrawConstellationLabels escreve os nomes IAU das constelações visíveis.
+// Deve ser chamada fora do modo 3D, depois de rl.EndMode3D.
+func (sim *Simulation) DrawConstellationLabels(camera rl.Camera3D) {
+	if !sim.ShowConstellations {
+		return
+	}
+	forward := rl.Vector3Subtract(camera.Target, camera.Position)
+	for _, c := range sim.Constellations {
+		// Ignora rótulos atrás da câmera
+		label := sim.orientSky(c.Label)
+		if rl.Vector3DotProduct(rl.Vector3Subtract(label, camera.Position), forward) <= 0 {
+			continue
+		}
+		pos := rl.GetWorldToScreen(label, camera)
+		width := rl.MeasureText(c.Name, 16)
+		rl.DrawText(c.Name, int32(pos.X)-width/2, int32(pos.Y), 16, rl.NewColor(140, 180, 255, 220))
+	}
+}
+
+// ─────────────────────────────────────────────
+// Rastros das órbitas
+
+// trailPoints é a capacidade de cada rastro: os da Lua e das outras luas
+// dão várias voltas na duração padrão.
+const trailPoints = 1024
+
+// trailBodies lista os corpos que deixam rastro: planetas, luas e o
+// asteroide escolhido com o mouse.
+func (sim *Simulation) trailBodies() []PickedBody {
+	var out []PickedBody
+	for _, p := range sim.Planets {
+		out = append(out, PickedBody{Planet: p})
+		for _, m := range p.Moons {
+			out = append(out, PickedBody{Planet: p, Moon: m})
+		}
+	}
+	if sim.Picked.Asteroid != nil {
+		out = append(out, sim.Picked)
+	}
+	return out
+}
+
+// updateTrails registra a posição de cada corpo, já no referencial da cena,
+// na data JD. Desligados, os rastros são descartados.
+func (sim *Simulation) updateTrails() {
+	if !sim.ShowTrails {
+		sim.Trails = nil
+		return
+	}
+	if sim.Trails == nil {
+		sim.Trails = make(map[PickedBody]*trail.Trail[rl.Vector3])
+	}
+	sim.updateFrameOrigin()
+	seen := make(map[PickedBody]bool)
+	for _, b := range sim.trailBodies() {
+		seen[b] = true
+		t := sim.Trails[b]
+		if t == nil {
+			t = trail.NewWithCapacity[rl.Vector3](sim.TrailDays, trailPoints)
+			sim.Trails[b] = t
+		}
+		t.Add(sim.toFrame(sim.info(b).Position), sim.JD)
+	}
+	for b := range sim.Trails {
+		if !seen[b] {
+			delete(sim.Trails, b)
+		}
+	}
+}
+
+// resetTrails apaga os rastros; usada quando o referencial muda, porque os
+// pontos guardados estão no referencial antigo.
+func (sim *Simulation) resetTrails() {
+	for _, t := range sim.Trails {
+		t.Reset()
+	}
+}
+
+// drawTrails desenha os rastros na cor de cada corpo, sumindo para o passado.
+func (sim *Simulation) drawTrails() {
+	for b, t := range sim.Trails {
+		col := rl.Gray
+		switch {
+		case b.Moon != nil:
+			col = b.Moon.Color
+		case b.Planet != nil:
+			col = b.Planet.Color
+		}
+		t.Segments(sim.JD, func(p, q rl.Vector3, age float64) {
+			c := col
+			c.A = uint8(200 * (1 - age))
+			rl.DrawLine3D(p, q, c)
+		})
+	}
+}
+
+// ─────────────────────────────────────────────
+// Seleção com o mouse (raio contra esferas)
+
+// pickMinRadius é o raio mínimo das esferas de seleção, para que asteroides e
+// luas pequenas ainda possam ser clicados.
+const pickMinRadius = 3
+
+// doubleClickSeconds é o intervalo máximo entre os cliques de um duplo clique.
+const doubleClickSeconds = 0.35
+
+// PickedBody aponta para o corpo escolhido com o mouse: uma lua (com o seu
+// planeta), um planeta, um asteroide ou o cometa. O valor zero não aponta
+// para nada.
+type PickedBody struct {
+	Planet   *Planet
+	Moon     *Moon
+	Asteroid *Asteroid
+	Comet    bool
+}
+
+// Valid informa se a referência aponta para algum corpo.
+func (b PickedBody) Valid() bool {
+	return b.Planet != nil || b.Asteroid != nil || b.Comet
+}
+
+// pickInfo descreve um corpo para a seleção e o painel de detalhes. Position
+// está na cena heliocêntrica; OrbitRadius (px) e Speed (px/frame) são
+// relativos ao primário Parent.
+type pickInfo struct {
+	Name, Parent string
+	Position     rl.Vector3
+	Radius       float32
+	OrbitRadius  float64
+	Speed        float64
+}
+
+// pickable lista os corpos que podem ser escolhidos com o mouse.
+func (sim *Simulation) pickable() []PickedBody {
+	var out []PickedBody
+	for _, p := range sim.Planets {
+		out = append(out, PickedBody{Planet: p})
+		for _, m := range p.Moons {
+			out = append(out, PickedBody{Planet: p, Moon: m})
+		}
+	}
+	for i := range sim.Asteroids {
+		out = append(out, PickedBody{Asteroid: &sim.Asteroids[i]})
+	}
+	return append(out, PickedBody{Comet: true})
+}
+
+// info devolve a descrição atual do corpo.
+func (sim *Simulation) info(b PickedBody) pickInfo {
+	switch {
+	case b.Moon != nil:
+		name := b.Planet.Name
+		for i, m := range b.Planet.Moons {
+			if m == b.Moon {
+				name = moonName(b.Planet, i)
+			}
+		}
+		return pickInfo{Name: name, Parent: b.Planet.Name, Position: b.Moon.Position(b.Planet.Position()),
+			Radius: b.Moon.Radius, OrbitRadius: b.Moon.OrbitRadius, Speed: b.Moon.Velocity().Norm()}
+	case b.Planet != nil:
+		p := b.Planet
+		return pickInfo{Name: p.Name, Parent: "Sol", Position: p.Position(), Radius: p.Radius,
+			OrbitRadius: p.OrbitRadius, Speed: math.Abs(p.OrbitRadius * p.OrbitSpeed)}
+	case b.Asteroid != nil:
+		a := b.Asteroid
+		name := "Asteroide"
+		for i := range sim.Asteroids {
+			if &sim.Asteroids[i] == a {
+				name = fmt.Sprintf("Asteroide %d", i+1)
+			}
+		}
+		pos := rl.NewVector3(float32(a.OrbitRadius*math.Cos(a.Angle)), 0, float32(a.OrbitRadius*math.Sin(a.Angle)))
+		return pickInfo{Name: name, Parent: "Sol", Position: pos, Radius: a.Radius,
+			OrbitRadius: a.OrbitRadius, Speed: math.Abs(a.OrbitRadius * a.OrbitSpeed)}
+	}
+	c := sim.Comet
+	return pickInfo{Name: "Cometa", Parent: "Sol", Position: c.Position, Radius: 4,
+		OrbitRadius: float64(rl.Vector3Length(c.Position)), Speed: c.Speed}
+}
+
+// Pick devolve o corpo mais próximo da câmera atingido pelo raio, que está
+// no referencial da cena (como a câmera).
+func (sim *Simulation) Pick(ray rl.Ray) (PickedBody, bool) {
+	var best PickedBody
+	bestDist := float32(math.Inf(1))
+	for _, b := range sim.pickable() {
+		in := sim.info(b)
+		hit := rl.GetRayCollisionSphere(ray, sim.toFrame(in.Position), max(in.Radius, pickMinRadius))
+		if hit.Hit && hit.Distance < bestDist {
+			best, bestDist = b, hit.Distance
+		}
+	}
+	return best, best.Valid()
+}
+
+// drawPicked destaca o corpo escolhido com uma esfera de arame.
+func (sim *Simulation) drawPicked() {
+	if !sim.Picked.Valid() {
+		return
+	}
+	in := sim.info(sim.Picked)
+	rl.DrawSphereWires(sim.toFrame(in.Position), max(in.Radius, pickMinRadius)*1.4, 8, 12, rl.NewColor(120, 255, 160, 200))
+}
+
+// DrawPicked escreve os detalhes do corpo escolhido. Deve ser chamada fora
+// do modo 3D.
+func (sim *Simulation) DrawPicked(x, y int32) {
+	if !sim.Picked.Valid() {
+		return
+	}
+	in := sim.info(sim.Picked)
+	lines := []string{
+		in.Name,
+		fmt.Sprintf("Raio = %.1f px", in.Radius),
+		fmt.Sprintf("Raio da órbita = %.1f px", in.OrbitRadius),
+		fmt.Sprintf("Velocidade = %.3f px/frame", in.Speed),
+		"Primário = " + in.Parent,
+	}
+	rl.DrawRectangle(x-8, y-8, 320, int32(len(lines))*22+12, rl.NewColor(0, 0, 0, 150))
+	for i, line := range lines {
+		rl.DrawText(line, x, y+int32(i)*22, 18, rl.White)
+	}
+}
+
+// ─────────────────────────────────────────────
+// Controle da câmera: voos suaves e trava em um corpo
+
+// cameraFlightSeconds é a duração de um voo da câmera, em segundos reais.
+const cameraFlightSeconds = 1.5
+
+// CameraController anima a câmera entre vistas e, com Lock, mantém um corpo
+// no centro enquanto ele orbita. A câmera continua em rl.Camera3D; o
+// controlador só a ajusta depois dos controles normais.
+type CameraController struct {
+	Lock   PickedBody // corpo travado no centro (valor zero: nenhum)
+	flight *cameraFlight
+}
+
+// cameraFlight é um voo em andamento de from até to. Num voo até um corpo
+// travado, o destino acompanha o corpo: offset é a posição final da câmera
+// relativa a ele.
+type cameraFlight struct {
+	from, to rl.Camera3D
+	offset   rl.Vector3
+	elapsed  float64
+}
+
+// Flying informa se há um voo em andamento, durante o qual a câmera não
+// responde ao mouse nem ao teclado.
+func (c *CameraController) Flying() bool {
+	return c.flight != nil
+}
+
+// FlyTo começa um voo da câmera atual até a vista to, soltando a trava.
+func (c *CameraController) FlyTo(camera, to rl.Camera3D) {
+	c.Lock = PickedBody{}
+	c.flight = &cameraFlight{from: camera, to: to}
+}
+
+// FlyToBody começa um voo da câmera atual até perto do corpo e trava a
+// câmera nele. A vista view dá a orientação de chegada (Up, fov e a direção
+// de onde a câmera olha o corpo).
+func (c *CameraController) FlyToBody(camera, view rl.Camera3D, sim *Simulation, b PickedBody) {
+	radius := max(sim.info(b).Radius, pickMinRadius)
+	dir := rl.Vector3Normalize(rl.Vector3Subtract(view.Position, view.Target))
+	c.Lock = b
+	c.flight = &cameraFlight{from: camera, to: view, offset: rl.Vector3Scale(dir, max(radius*10, 30))}
+}
+
+// Update avança o voo em dt segundos ou, sem voo, desloca a câmera junto com
+// o corpo travado, que fica no centro da tela.
+func (c *CameraController) Update(camera *rl.Camera3D, sim *Simulation, dt float64) {
+	var body rl.Vector3
+	if c.Lock.Valid() {
+		body = sim.toFrame(sim.info(c.Lock).Position)
+	}
+	f := c.flight
+	if f == nil {
+		if c.Lock.Valid() {
+			camera.Position = rl.Vector3Add(camera.Position, rl.Vector3Subtract(body, camera.Target))
+			camera.Target = body
+		}
+		return
+	}
+	to := f.to
+	if c.Lock.Valid() {
+		to.Target, to.Position = body, rl.Vector3Add(body, f.offset)
+	}
+	f.elapsed += dt
+	*camera = lerpCamera(f.from, to, easeInOut(math.Min(f.elapsed/cameraFlightSeconds, 1)))
+	if f.elapsed >= cameraFlightSeconds {
+		c.flight = nil
+	}
+}
+
+// easeInOut suaviza a fração t do voo: parte e chega com velocidade zero.
+func easeInOut(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	u := -2*t + 2
+	return 1 - u*u*u/2
+}
+
+// lerpCamera interpola duas câmeras na fração s; a projeção é a do destino.
+func lerpCamera(a, b rl.Camera3D, s float64) rl.Camera3D {
+	t := float32(s)
+	out := b
+	out.Position = rl.Vector3Lerp(a.Position, b.Position, t)
+	out.Target = rl.Vector3Lerp(a.Target, b.Target, t)
+	out.Up = rl.Vector3Normalize(rl.Vector3Lerp(a.Up, b.Up, t))
+	out.Fovy = a.Fovy + (b.Fovy-a.Fovy)*t
+	return out
+}
+
+// ─────────────────────────────────────────────
+// Modo planetário: o céu visto de um ponto da superfície da Terra
+
+// planetariumRadius é o raio da esfera celeste da cena do planetário.
+const planetariumRadius = 100
+
+// Planetarium guarda o observador e a direção de visada do modo planetário.
+type Planetarium struct {
+	Observer astro.Observer
+	Yaw      float64 // azimute da visada (radianos, a partir do norte, para leste)
+	Pitch    float64 // altura da visada (radianos)
+	Fovy     float32
+}
+
+// NewPlanetarium cria um planetário olhando para o sul, um pouco acima do horizonte.
+func NewPlanetarium(latDeg, lonDeg float64) *Planetarium {
+	return &Planetarium{
+		Observer: astro.Observer{Lat: latDeg * math.Pi / 180, Lon: lonDeg * math.Pi / 180},
+		Yaw:      math.Pi,
+		Pitch:    20 * math.Pi / 180,
+		Fovy:     60,
+	}
+}
+
+// horizontalToScene converte (altura, azimute) para a cena do planetário:
+// +Y aponta para o zênite, -Z para o norte e +X para o leste.
+func horizontalToScene(alt, az, r float64) rl.Vector3 {
+	ca := math.Cos(alt)
+	return rl.NewVector3(
+		float32(r*ca*math.Sin(az)),
+		float32(r*math.Sin(alt)),
+		float32(-r*ca*math.Cos(az)),
+	)
+}
+
+// HandleInput gira a visada com as setas ou arrastando o mouse e ajusta o zoom com a roda.
+func (pl *Planetarium) HandleInput() {
+	step := float64(pl.Fovy) / 60 * 0.02
+	if rl.IsKeyDown(rl.KeyLeft) {
+		pl.Yaw -= step
+	}
+	if rl.IsKeyDown(rl.KeyRight) {
+		pl.Yaw += step
+	}
+	if rl.IsKeyDown(rl.KeyUp) {
+		pl.Pitch += step
+	}
+	if rl.IsKeyDown(rl.KeyDown) {
+		pl.Pitch -= step
+	}
+	if rl.IsMouseButtonDown(rl.MouseButtonLeft) {
+		delta := rl.GetMouseDelta()
+		pl.Yaw -= float64(delta.X) * step * 0.2
+		pl.Pitch += float64(delta.Y) * step * 0.2
+	}
+	limit := 89 * math.Pi / 180
+	pl.Pitch = math.Max(-limit, math.Min(limit, pl.Pitch))
+	pl.Yaw = math.Mod(pl.Yaw+2*math.Pi, 2*math.Pi)
+
+	pl.Fovy *= 1 - 0.1*rl.GetMouseWheelMove()
+	pl.Fovy = float32(math.Max(10, math.Min(100, float64(pl.Fovy))))
+}
+
+// Camera devolve a câmera no centro da esfera, apontada para a visada atual.
+func (pl *Planetarium) Camera() rl.Camera3D {
+	return rl.Camera3D{
+		Position:   rl.NewVector3(0, 0, 0),
+		Target:     horizontalToScene(pl.Pitch, pl.Yaw, 1),
+		Up:         rl.NewVector3(0, 1, 0),
+		Fovy:       pl.Fovy,
+		Projection: rl.CameraPerspective,
+	}
+}
+
+// skyBody é um corpo do Sistema Solar como aparece no céu do observador.
+type skyBody struct {
+	Name     string
+	Position rl.Vector3
+	Alt      float64
+	Radius   float32
+	Color    rl.Color
+}
+
+// bodies calcula as posições aparentes do Sol, da Lua e dos planetas da simulação.
+func (pl *Planetarium) bodies(sim *Simulation) []skyBody {
+	list := []skyBody{
+		{Name: astro.Sun.String(), Radius: 1.2, Color: rl.Yellow},
+		{Name: astro.Moon.String(), Radius: 1.2, Color: rl.LightGray},
+	}
+	for _, p := range sim.Planets {
+		b, ok := astro.BodyByName(p.Name)
+		if !ok || b == astro.Earth {
+			continue
+		}
+		list = append(list, skyBody{Name: b.String(), Radius: 0.5, Color: p.Color})
+	}
+	for i := range list {
+		b, _ := astro.BodyByName(list[i].Name)
+		alt, az := pl.Observer.Horizontal(pl.Observer.Apparent(b, sim.JD), sim.JD)
+		list[i].Alt = alt
+		list[i].Position = horizontalToScene(alt, az, planetariumRadius)
+	}
+	return list
+}
+
+// SkyColor escurece o céu conforme a altura do Sol (crepúsculo entre -12° e +5°).
+// Retorna a cor do fundo e a fração de "dia" (0 à noite, 1 de dia).
+func (pl *Planetarium) SkyColor(sim *Simulation) (rl.Color, float64) {
+	sunAlt, _ := pl.Observer.Horizontal(pl.Observer.Apparent(astro.Sun, sim.JD), sim.JD)
+	t := (sunAlt*180/math.Pi + 12) / 17
+	t = math.Max(0, math.Min(1, t))
+	night := rl.NewColor(3, 4, 16, 255)
+	day := rl.NewColor(80, 130, 200, 255)
+	return rl.NewColor(
+		uint8(float64(night.R)+t*float64(int(day.R)-int(night.R))),
+		uint8(float64(night.G)+t*float64(int(day.G)-int(night.G))),
+		uint8(float64(night.B)+t*float64(int(day.B)-int(night.B))),
+		255,
+	), t
+}
+
+// Draw desenha o chão, o horizonte, as estrelas, as constelações e os corpos.
+// Deve ser chamada dentro de rl.BeginMode3D com a câmera de Camera.
+func (pl *Planetarium) Draw(sim *Simulation) {
+	_, daylight := pl.SkyColor(sim)
+
+	// Estrelas acima do horizonte, apagadas durante o dia
+	for _, s := range sim.SkyCatalog {
+		alt, az := pl.Observer.Horizontal(s.Direction(), sim.JD)
+		if alt < 0 {
+			continue
+		}
+		col := s.Color()
+		col.A = uint8(float64(col.A) * (1 - daylight))
+		rl.DrawSphereEx(horizontalToScene(alt, az, planetariumRadius), float32(s.Size())*0.12, 4, 6, col)
+	}
+
+	if sim.ShowConstellations {
+		lineColor := rl.NewColor(90, 140, 220, uint8(160*(1-daylight)))
+		for _, seg := range sim.SkySegments {
+			alt1, az1 := pl.Observer.Horizontal(seg[0], sim.JD)
+			alt2, az2 := pl.Observer.Horizontal(seg[1], sim.JD)
+			if alt1 < 0 && alt2 < 0 {
+				continue
+			}
+			rl.DrawLine3D(horizontalToScene(alt1, az1, planetariumRadius),
+				horizontalToScene(alt2, az2, planetariumRadius), lineColor)
+		}
+	}
+
+	for _, b := range pl.bodies(sim) {
+		rl.DrawSphere(b.Position, b.Radius, b.Color)
+	}
+
+	// Chão logo abaixo do observador: esconde o que está sob o horizonte
+	rl.DrawPlane(rl.NewVector3(0, -0.5, 0), rl.NewVector2(4*planetariumRadius, 4*planetariumRadius), rl.NewColor(20, 35, 20, 255))
+	rl.DrawCircle3D(rl.NewVector3(0, 0, 0), planetariumRadius, rl.NewVector3(1, 0, 0), 90, rl.NewColor(120, 160, 120, 255))
+}
+
+// DrawLabels escreve os pontos cardeais e os nomes dos corpos visíveis.
+// Deve ser chamada depois de rl.EndMode3D.
+func (pl *Planetarium) DrawLabels(sim *Simulation, camera rl.Camera3D) {
+	forward := rl.Vector3Subtract(camera.Target, camera.Position)
+	label := func(pos rl.Vector3, text string, size int32, col rl.Color) {
+		if rl.Vector3DotProduct(pos, forward) <= 0 {
+			return
+		}
+		p := rl.GetWorldToScreen(pos, camera)
+		rl.DrawText(text, int32(p.X)-rl.MeasureText(text, size)/2, int32(p.Y), size, col)
+	}
+
+	cardinals := []string{"N", "L", "S", "O"}
+	for i, name := range cardinals {
+		pos := horizontalToScene(0.02, float64(i)*math.Pi/2, planetariumRadius)
+		label(pos, name, 24, rl.NewColor(255, 200, 120, 255))
+	}
+	for _, b := range pl.bodies(sim) {
+		if b.Alt < 0 {
+			continue
+		}
+		label(rl.NewVector3(b.Position.X, b.Position.Y-b.Radius-1, b.Position.Z), b.Name, 16, rl.White)
+	}
+}
+
+func main() {
+	// Observador e data inicial do modo planetário (padrão: São Paulo, agora)
+	lat := flag.Float64("lat", -23.55, "latitude do observador em graus (norte positivo)")
+	lon := flag.Float64("lon", -46.63, "longitude do observador em graus (leste positivo)")
+	date := flag.String("data", "", "data e hora UTC iniciais no formato RFC 3339 (vazio: agora)")
+	porkchop := flag.String("porkchop", "", "CSV gravado pelo comando porkchop, para escolher uma transferência")
+	format := flag.String("formato", "avi", "formato da gravação da tecla F9: png, gif ou avi")
+	fps := flag.Float64("fps", 30, "quadros por segundo simulado da gravação")
+	seed := flag.Int64("semente", 0, "semente das partes aleatórias da cena (0: a hora atual)")
+	resolution := flag.String("resolucao", "", "resolução dos pôsteres da tecla F10, como 7680x4320 (vazio: a da tela)")
+	poster := flag.String("poster", "", "salva um pôster da cena inicial neste PNG e sai")
+	trailDays := flag.Float64("rastro", 730, "duração dos rastros da tecla 4, em dias")
+	flag.Parse()
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	recordFormat, err := recorder.ParseFormat(*format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var rec *recorder.Recorder // gravação em andamento (tecla F9)
+
+	// Define a flag para full screen antes de inicializar a janela
+	rl.SetConfigFlags(rl.FlagFullscreenMode)
+
+	// Obtém a resolução do monitor principal para definir a janela full screen
+	screenWidth := int32(rl.GetMonitorWidth(0))
+	screenHeight := int32(rl.GetMonitorHeight(0))
+	rl.InitWindow(screenWidth, screenHeight, "Simulação 3D Realista do Sistema Solar - Câmeras, Física & Colisões")
+	defer rl.CloseWindow()
+	rl.SetTargetFPS(60)
+
+	// Carrega a imagem de fundo (certifique-se de que o arquivo "space.jpg" esteja na pasta "resources")
+	backgroundTexture := rl.LoadTexture("space.jpg")
+	defer rl.UnloadTexture(backgroundTexture)
+
+	// Cria a câmera 3D com parâmetros iniciais (modo normal)
+	camera := rl.Camera3D{
+		Position:   rl.NewVector3(0, 300, 600),
+		Target:     rl.NewVector3(0, 0, 0),
+		Up:         rl.NewVector3(0, 1, 0),
+		Fovy:       45,
+		Projection: rl.CameraPerspective,
+	}
+
+	// Salvamos o estado normal da câmera para restaurá-lo depois do modo Top View.
+	normalCamera := camera
+
+	// Variável que guarda o modo de câmera para os controles normais (Orbital ou Livre)
+	currentCameraMode := CameraOrbital
+
+	// Variável que indica se o modo Top View está ativo
+	topViewEnabled := false
+
+	// Voos suaves da câmera e trava num corpo escolhido (tecla M)
+	cameraControl := &CameraController{}
+
+	// Gera o mesh e o modelo para o anel de Saturno
+	ringMesh := generateRingMesh(1.5, 2.0, 100)
+	ringModel := rl.LoadModelFromMesh(ringMesh)
+	defer rl.UnloadModel(ringModel)
+
+	sim := NewSimulation(*seed)
+	sim.TrailDays = *trailDays
+	posterWidth, posterHeight := int(screenWidth), int(screenHeight)
+	if *resolution != "" {
+		if posterWidth, posterHeight, err = recorder.ParseResolution(*resolution); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *date != "" {
+		t, err := time.Parse(time.RFC3339, *date)
+		if err != nil {
+			log.Fatalf("data inválida %q: %v", *date, err)
+		}
+		sim.JD = astro.JulianDate(t)
+	}
+	if *porkchop != "" {
+		if err := sim.LoadPorkchop(*porkchop); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Modo planetário: céu visto da superfície da Terra, com a Terra girando
+	planetarium := NewPlanetarium(*lat, *lon)
+	planetariumEnabled := false
+	orbitalTimeScale := sim.TimeScale // velocidade do relógio a restaurar ao sair do planetário
+
+	// flyToBody voa até o corpo e trava a câmera nele; saindo do Top View, o
+	// voo chega na orientação da vista normal guardada
+	flyToBody := func(b PickedBody) {
+		view := camera
+		if topViewEnabled {
+			view, topViewEnabled = normalCamera, false
+		}
+		cameraControl.FlyToBody(camera, view, sim, b)
+	}
+
+	for !rl.WindowShouldClose() {
+		sim.Update()
+
+		// Alterna o modo planetário; nele o relógio anda um minuto por frame, e
+		// na volta recupera a velocidade que tinha antes
+		if rl.IsKeyPressed(rl.KeyThree) {
+			planetariumEnabled = !planetariumEnabled
+			if planetariumEnabled {
+				orbitalTimeScale, sim.TimeScale = sim.TimeScale, 1.0/1440
+			} else {
+				sim.TimeScale = orbitalTimeScale
+			}
+		}
+		if rl.IsKeyPressed(rl.KeyF9) {
+			rec = rlcapture.ToggleRecording(rec, recordFormat, *fps)
+		}
+		// Ajusta a velocidade do relógio
+		if rl.IsKeyPressed(rl.KeyRightBracket) {
+			sim.TimeScale *= 2
+		}
+		if rl.IsKeyPressed(rl.KeyLeftBracket) {
+			sim.TimeScale /= 2
+		}
+
+		// Alterna entre o modo Top View e o normal ao pressionar a tecla P,
+		// voando entre as duas vistas. No modo Top View, a câmera fica fixa,
+		// sem reagir ao mouse.
+		if rl.IsKeyPressed(rl.KeyP) {
+			if !topViewEnabled {
+				normalCamera = camera
+				top := camera
+				top.Position = rl.NewVector3(0, 800, 0)
+				top.Target = rl.NewVector3(0, 0, 0)
+				top.Up = rl.NewVector3(0, 0, -1)
+				top.Projection = rl.CameraPerspective
+				top.Fovy = 45
+				cameraControl.FlyTo(camera, top)
+				topViewEnabled = true
+			} else {
+				topViewEnabled = false
+				cameraControl.FlyTo(camera, normalCamera)
+			}
+		}
+
+		// Alterna as figuras das constelações
+		if rl.IsKeyPressed(rl.KeyC) {
+			sim.ShowConstellations = !sim.ShowConstellations
+		}
+		if rl.IsKeyPressed(rl.KeyFour) {
+			sim.ShowTrails = !sim.ShowTrails
+		}
+		// Alterna o referencial da cena e o referencial girante
+		if rl.IsKeyPressed(rl.KeyF) {
+			sim.NextFrame()
+		}
+		if rl.IsKeyPressed(rl.KeyR) {
+			sim.NextRotatingPlanet()
+		}
+		// Planejador de transferências: H liga, O troca a origem, N o destino
+		// e B alterna entre Hohmann e bi-elíptica
+		if rl.IsKeyPressed(rl.KeyH) {
+			if sim.Transfer == nil {
+				sim.Transfer = sim.NewTransferPlan()
+			} else {
+				sim.Transfer = nil
+			}
+		}
+		if plan := sim.Transfer; plan != nil {
+			switch {
+			case rl.IsKeyPressed(rl.KeyO):
+				plan.From = sim.cyclePlanet(plan.From, plan.To)
+				plan.Recalculate()
+			case rl.IsKeyPressed(rl.KeyN):
+				plan.To = sim.cyclePlanet(plan.To, plan.From)
+				plan.Recalculate()
+			case rl.IsKeyPressed(rl.KeyB):
+				plan.BiElliptic = !plan.BiElliptic
+				plan.Recalculate()
+			}
+		}
+		// Nave: V lança ou remove; Enter cria um nó, Tab escolhe o nó,
+		// Backspace o apaga, G troca o componente, - e = mudam o Δv e
+		// , e . movem o nó no tempo (segurando as teclas)
+		if rl.IsKeyPressed(rl.KeyV) {
+			if sim.Craft == nil {
+				sim.LaunchCraft()
+			} else {
+				sim.Craft = nil
+			}
+		}
+		// Porkchop: K mostra o painel e um clique numa célula lança a sonda;
+		// J alterna entre as velocidades da simulação e as efemérides
+		if rl.IsKeyPressed(rl.KeyK) && sim.Porkchop != nil {
+			sim.ShowPorkchop = !sim.ShowPorkchop
+		}
+		if sim.ShowPorkchop && sim.Porkchop != nil && rl.IsMouseButtonPressed(rl.MouseButtonLeft) {
+			if c, ok := sim.porkchopCellAt(porkchopPanel(screenWidth), rl.GetMousePosition()); ok && c.Valid {
+				if err := sim.LoadTransfer(c); err != nil {
+					log.Print(err)
+				}
+			}
+		}
+		if rl.IsKeyPressed(rl.KeyJ) {
+			sim.Ephemeris = !sim.Ephemeris
+			sim.resetTrails()
+		}
+		// Eventos: Z abre os eclipses e trânsitos, U as conjunções, oposições,
+		// elongações e estações; um clique numa linha salta para o evento
+		if rl.IsKeyPressed(rl.KeyZ) {
+			sim.ToggleEvents("Eclipses e trânsitos", eclipseTable)
+		}
+		if rl.IsKeyPressed(rl.KeyU) {
+			sim.ToggleEvents("Fenômenos vistos da Terra", sim.phenomenaTable)
+		}
+		if sim.Events != nil && rl.IsMouseButtonPressed(rl.MouseButtonLeft) {
+			if row, ok := sim.eventAt(sim.eventPanel(screenWidth), rl.GetMousePosition()); ok {
+				sim.JumpToEvent(row)
+			}
+		}
+
+		// Um clique na cena (fora dos painéis) escolhe o corpo sob o mouse ou
+		// limpa a escolha; o duplo clique voa até o corpo e trava a câmera
+		// nele, e M liga ou solta a trava no corpo escolhido
+		mouse := rl.GetMousePosition()
+		onPanel := (sim.ShowPorkchop && sim.Porkchop != nil && rl.CheckCollisionPointRec(mouse, porkchopPanel(screenWidth))) ||
+			(sim.Events != nil && rl.CheckCollisionPointRec(mouse, sim.eventPanel(screenWidth)))
+		if !planetariumEnabled && !onPanel && rl.IsMouseButtonPressed(rl.MouseButtonLeft) {
+			picked, ok := sim.Pick(rl.GetScreenToWorldRay(mouse, camera))
+			now := rl.GetTime()
+			if ok && picked == sim.Picked && now-sim.lastClick < doubleClickSeconds {
+				flyToBody(picked)
+			}
+			sim.Picked, sim.lastClick = picked, now
+		}
+		if rl.IsKeyPressed(rl.KeyM) && !planetariumEnabled {
+			if cameraControl.Lock.Valid() {
+				cameraControl.Lock = PickedBody{}
+			} else if sim.Picked.Valid() {
+				flyToBody(sim.Picked)
+			}
+		}
+
+		// X escolhe o corpo do painel de elementos orbitais; I liga as esferas
+		// de influência e de Hill desse corpo (ou de todos os planetas)
+		if rl.IsKeyPressed(rl.KeyX) {
+			sim.NextSelection()
+		}
+		if rl.IsKeyPressed(rl.KeyI) {
+			sim.ToggleSpheres()
+		}
+
+		// Y lança uma sonda de sobrevoo pelo próximo planeta da lista
+		if rl.IsKeyPressed(rl.KeyY) {
+			if target := sim.NextFlybyTarget(); target != nil {
+				sim.LaunchFlyby(target)
+			} else {
+				sim.Notifications = append(sim.Notifications, Notification{Text: "Nenhum planeta tem esfera de influência maior que ele mesmo", Time: sim.Time})
+			}
+		}
+		if sim.Craft != nil {
+			if rl.IsKeyPressed(rl.KeyEnter) {
+				sim.AddNode()
+			}
+			if rl.IsKeyPressed(rl.KeyTab) && len(sim.Craft.Nodes) > 0 {
+				sim.SelectedNode = (sim.SelectedNode + 1) % len(sim.Craft.Nodes)
+			}
+			if rl.IsKeyPressed(rl.KeyBackspace) {
+				sim.RemoveNode()
+			}
+			if rl.IsKeyPressed(rl.KeyG) {
+				sim.NodeComponent = (sim.NodeComponent + 1) % 3
+			}
+			dv, dt := 0.0, 0.0
+			if rl.IsKeyDown(rl.KeyEqual) {
+				dv += 0.002
+			}
+			if rl.IsKeyDown(rl.KeyMinus) {
+				dv -= 0.002
+			}
+			if rl.IsKeyDown(rl.KeyPeriod) {
+				dt += 2
+			}
+			if rl.IsKeyDown(rl.KeyComma) {
+				dt -= 2
+			}
+			if dv != 0 || dt != 0 {
+				sim.EditNode(dv, dt)
+			}
+		}
+		if rl.IsKeyPressed(rl.KeyL) {
+			sim.LagrangePair = (sim.LagrangePair + 1) % (len(lagrangePairs) + 1)
+		}
+		// Cria (ou recria) os troianos do planeta travado, ou de Júpiter
+		if rl.IsKeyPressed(rl.KeyT) {
+			host := sim.RotatingPlanet
+			if host == nil {
+				host = sim.planetByBody(astro.Jupiter)
+			}
+			sim.SpawnTrojans(host)
+		}
+
+		// Se não estiver no modo Top View, atualiza a câmera com base nas entradas do usuário.
+		if planetariumEnabled {
+			planetarium.HandleInput()
+		} else {
+			if !topViewEnabled && !cameraControl.Flying() {
+				if rl.IsKeyPressed(rl.KeyOne) {
+					currentCameraMode = CameraOrbital
+				}
+				if rl.IsKeyPressed(rl.KeyTwo) {
+					currentCameraMode = CameraFree
+				}
+				rl.UpdateCamera(&camera, rl.CameraMode(currentCameraMode))
+			}
+			cameraControl.Update(&camera, sim, float64(rl.GetFrameTime()))
+		}
+
+		// Pôster: F10 (ou -poster, que sai em seguida) desenha a cena atual,
+		// sem textos, na resolução de -resolucao
+		if rl.IsKeyPressed(rl.KeyF10) || *poster != "" {
+			name, shotCamera := *poster, camera
+			if name == "" {
+				name = recorder.PosterName(time.Now())
+			}
+			var img *image.RGBA
+			if planetariumEnabled {
+				sky, _ := planetarium.SkyColor(sim)
+				shotCamera = planetarium.Camera()
+				img = rlcapture.RenderPoster(posterWidth, posterHeight, shotCamera, sky, nil, func() { planetarium.Draw(sim) })
+			} else {
+				background := func(part rl.Rectangle, w, h float32) {
+					tw, th := float32(backgroundTexture.Width), float32(backgroundTexture.Height)
+					rl.DrawTexturePro(backgroundTexture, rl.NewRectangle(part.X*tw, part.Y*th, part.Width*tw, part.Height*th),
+						rl.NewRectangle(0, 0, w, h), rl.NewVector2(0, 0), 0, rl.White)
+				}
+				img = rlcapture.RenderPoster(posterWidth, posterHeight, shotCamera, rl.Black, background, func() { sim.Draw3D(ringModel) })
+			}
+			meta := []recorder.Metadata{
+				{Key: "Software", Value: "Simulação 3D Realista do Sistema Solar"},
+				{Key: "Simulation Date", Value: fmt.Sprintf("%s UTC (JD %.5f)", astro.TimeFromJulian(sim.JD).Format("2006-01-02 15:04:05"), sim.JD)},
+				rlcapture.CameraMetadata(shotCamera),
+				{Key: "Seed", Value: fmt.Sprint(sim.Seed)},
+			}
+			if err := recorder.WritePNGMetadata(name, img, meta); err != nil {
+				log.Print(err)
+			} else {
+				log.Printf("pôster salvo em %s (%dx%d)", name, posterWidth, posterHeight)
+			}
+			if *poster != "" {
+				return
+			}
+		}
+
+		rl.BeginDrawing()
+		if planetariumEnabled {
+			skyColor, _ := planetarium.SkyColor(sim)
+			rl.ClearBackground(skyColor)
+			skyCamera := planetarium.Camera()
+			rl.BeginMode3D(skyCamera)
+			planetarium.Draw(sim)
+			rl.EndMode3D()
+			planetarium.DrawLabels(sim, skyCamera)
+
+			observer := fmt.Sprintf("Planetário: lat %.2f°, lon %.2f° | %s UTC | passo %.1f min/frame",
+				*lat, *lon, astro.TimeFromJulian(sim.JD).Format("2006-01-02 15:04"), sim.TimeScale*1440)
+			rl.DrawText(observer, 10, 10, 20, rl.White)
+			rl.DrawText("Setas ou mouse: olhar | Roda: zoom | [ ]: velocidade do tempo | C: Constelações | 3: Sair | F9: Gravar", 10, 40, 20, rl.White)
+			if sim.SkyStatus != "" {
+				rl.DrawText(sim.SkyStatus, 10, 70, 20, rl.Orange)
+			}
+			rec = rlcapture.RecordFrame(rec, screenWidth)
+			rl.EndDrawing()
+			continue
+		}
+
+		// Limpa o frame
+		rl.ClearBackground(rl.Black)
+
+		// Desenha a imagem de fundo esticada para cobrir toda a tela
+		rl.DrawTexturePro(
+			backgroundTexture,
+			rl.NewRectangle(0, 0, float32(backgroundTexture.Width), float32(backgroundTexture.Height)),
+			rl.NewRectangle(0, 0, float32(screenWidth), float32(screenHeight)),
+			rl.NewVector2(0, 0),
+			0,
+			rl.White,
+		)
+
+		rl.BeginMode3D(camera)
+		sim.Draw3D(ringModel)
+		rl.EndMode3D()
+		sim.DrawConstellationLabels(camera)
+		sim.DrawLagrangeLabels(camera)
+		sim.DrawPorkchop(screenWidth)
+		sim.DrawEvents(screenWidth)
+		sim.DrawOrbitalElements(20, 300)
+		sim.DrawPicked(screenWidth-340, screenHeight-200)
+		sim.DrawNotifications(screenWidth-560, 400)
+		sim.DrawSphereScale(screenWidth-560, 374)
+
+		// Exibe informações na tela
+		modeText := ""
+		if topViewEnabled {
+			modeText = "Top View"
+		} else {
+			if currentCameraMode == CameraOrbital {
+				modeText = "Orbital"
+			} else {
+				modeText = "Livre"
+			}
+		}
+		if cameraControl.Lock.Valid() {
+			modeText += " | Seguindo " + sim.info(cameraControl.Lock).Name
+		}
+		rl.DrawText("Simulação 3D Realista do Sistema Solar", 10, 10, 20, rl.White)
+		rl.DrawText("Modo da Câmera: "+modeText, 10, 40, 20, rl.White)
+		rl.DrawText("Pressione 1: Orbital | 2: Livre (modo normal)", 10, 70, 20, rl.White)
+		rl.DrawText("Pressione P: Alternar Top View | F9: Gravar vídeo | F10: Pôster", 10, 100, 20, rl.White)
+		rl.DrawText("Pressione C: Constelações | 3: Planetário | Z: Eclipses | U: Fenômenos", 10, 130, 20, rl.White)
+		rl.DrawText("Pressione F: Referencial ("+sim.FrameName()+") | R: Girante | T: Troianos | 4: Rastros", 10, 160, 20, rl.White)
+		rl.DrawText("Pressione L: Pontos de Lagrange ("+sim.LagrangePairName()+") | X: Elementos orbitais | I: Esferas | Clique: Escolher | Duplo clique: Ir até | M: Seguir", 10, 190, 20, rl.White)
+		if sim.Craft != nil {
+			rl.DrawText(sim.CraftSummary(), 10, screenHeight-60, 20, rl.White)
+			rl.DrawText("V: Remover nave | Enter: Novo nó | Tab: Próximo | Backspace: Apagar | G: Componente | - =: dv | , .: Tempo", 10, screenHeight-30, 20, rl.White)
+		} else {
+			rl.DrawText("Pressione V: Lançar nave | Y: Sobrevoo", 10, screenHeight-30, 20, rl.White)
+		}
+		if p := sim.TransferProbe; p != nil {
+			day := math.Max(0, math.Min(sim.JD, p.Cell.Arrival)-p.Cell.Departure)
+			rl.DrawText(fmt.Sprintf("Sonda %s -> %s: partida %s, chegada %s | dia %.0f de %.0f | J: Efemérides",
+				p.Cell.From, p.Cell.To, astro.TimeFromJulian(p.Cell.Departure).Format("2006-01-02"),
+				astro.TimeFromJulian(p.Cell.Arrival).Format("2006-01-02"), day, p.Cell.Arrival-p.Cell.Departure),
+				10, screenHeight-120, 20, rl.White)
+		}
+		if sim.Flyby != nil {
+			rl.DrawText(sim.FlybyModel(), 10, screenHeight-150, 20, rl.LightGray)
+			rl.DrawText(sim.FlybySummary(), 10, screenHeight-90, 20, rl.White)
+		}
+		if sim.Transfer != nil {
+			rl.DrawText("Transferência "+sim.Transfer.Summary(), 10, 220, 20, rl.White)
+			rl.DrawText("H: Fechar | O: Origem | N: Destino | B: Hohmann/bi-elíptica", 10, 250, 20, rl.White)
+		} else {
+			rl.DrawText("Pressione H: Planejador de transferências", 10, 220, 20, rl.White)
+		}
+
+		rec = rlcapture.RecordFrame(rec, screenWidth)
+		rl.EndDrawing()
+	}
+
+	// Fecha a gravação que ficou aberta ao sair
+	if rec != nil {
+		rlcapture.ToggleRecording(rec, recordFormat, *fps)
 	}
 }
@@ -0,0 +1,82 @@
+// Package trail guarda o rastro dos corpos dos visualizadores: as posições
+// recentes num buffer circular de capacidade fixa, com a duração do rastro
+// medida no tempo da simulação e não em frames. Não depende de nenhuma
+// biblioteca gráfica; P é o tipo de ponto de cada visualizador.
+package trail
+
+// DefaultCapacity é o número de pontos de um rastro criado com New.
+const DefaultCapacity = 256
+
+// Trail é o rastro de um corpo: até len(points) posições, cada uma com o
+// instante em que foi registrada. Os pontos ficam espaçados de pelo menos
+// Length/capacidade no tempo, de modo que o buffer cobre a duração inteira
+// qualquer que seja a velocidade do relógio.
+type Trail[P any] struct {
+	Length float64 // duração do rastro, na unidade dos instantes
+	points []P
+	times  []float64
+	head   int // posição do ponto mais recente
+	count  int
+}
+
+// New cria um rastro vazio com a duração dada e DefaultCapacity pontos.
+func New[P any](length float64) *Trail[P] {
+	return NewWithCapacity[P](length, DefaultCapacity)
+}
+
+// NewWithCapacity cria um rastro vazio com capacidade para n pontos.
+func NewWithCapacity[P any](length float64, n int) *Trail[P] {
+	return &Trail[P]{Length: length, points: make([]P, n), times: make([]float64, n)}
+}
+
+// Add registra a posição p no instante now. Enquanto o último ponto estiver
+// perto demais do anterior, ele é substituído, para o rastro seguir o corpo
+// sem gastar o buffer; um relógio que volta ou salta mais que Length
+// recomeça o rastro.
+func (t *Trail[P]) Add(p P, now float64) {
+	if t.count > 0 {
+		last := t.times[t.head]
+		switch {
+		case now < last || now-last > t.Length:
+			t.Reset()
+		case t.count > 1 && last-t.times[t.prev(t.head)] < t.Length/float64(len(t.points)):
+			t.points[t.head], t.times[t.head] = p, now
+			return
+		}
+	}
+	if t.count > 0 {
+		t.head = (t.head + 1) % len(t.points)
+	}
+	t.points[t.head], t.times[t.head] = p, now
+	t.count = min(t.count+1, len(t.points))
+}
+
+func (t *Trail[P]) prev(i int) int {
+	return (i - 1 + len(t.points)) % len(t.points)
+}
+
+// Reset apaga o rastro.
+func (t *Trail[P]) Reset() {
+	t.head, t.count = 0, 0
+}
+
+// Len é o número de pontos guardados.
+func (t *Trail[P]) Len() int {
+	return t.count
+}
+
+// Segments chama fn para cada trecho do rastro, do mais recente para o mais
+// antigo, com a idade do trecho no instante now como fração de Length (0 no
+// corpo, 1 na ponta). Trechos mais velhos que Length são omitidos.
+func (t *Trail[P]) Segments(now float64, fn func(a, b P, age float64)) {
+	i := t.head
+	for k := 1; k < t.count; k++ {
+		j := t.prev(i)
+		age := (now - t.times[j]) / t.Length
+		if age > 1 {
+			return
+		}
+		fn(t.points[i], t.points[j], max(0, age))
+		i = j
+	}
+}
@@ -0,0 +1,100 @@
+package trail
+
+import (
+	"math"
+	"testing"
+)
+
+// segments devolve os trechos do rastro no instante now.
+func segments(t *Trail[float64], now float64) (pairs [][2]float64, ages []float64) {
+	t.Segments(now, func(a, b float64, age float64) {
+		pairs = append(pairs, [2]float64{a, b})
+		ages = append(ages, age)
+	})
+	return pairs, ages
+}
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		name     string
+		length   float64
+		capacity int
+		times    []float64 // um ponto por instante, com valor igual ao instante
+		wantLen  int
+		newest   float64
+	}{
+		{"vazio", 1, 4, nil, 0, 0},
+		{"um ponto", 1, 4, []float64{0}, 1, 0},
+		{"espaçados", 4, 4, []float64{0, 1, 2, 3}, 4, 3},
+		{"buffer cheio descarta o mais antigo", 8, 4, []float64{0, 2, 4, 6, 8, 10}, 4, 10},
+		{"pontos próximos substituem o último", 4, 4, []float64{0, 1, 1.2, 1.5, 2.5}, 3, 2.5},
+		{"relógio que volta recomeça", 4, 4, []float64{0, 1, 2, 0.5}, 1, 0.5},
+		{"salto maior que Length recomeça", 4, 4, []float64{0, 1, 10}, 1, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewWithCapacity[float64](tt.length, tt.capacity)
+			for _, now := range tt.times {
+				tr.Add(now, now)
+			}
+			if tr.Len() != tt.wantLen {
+				t.Fatalf("Len = %d, esperado %d", tr.Len(), tt.wantLen)
+			}
+			pairs, _ := segments(tr, tt.newest)
+			if len(pairs) > 0 && pairs[0][0] != tt.newest {
+				t.Errorf("ponto mais recente %v, esperado %v", pairs[0][0], tt.newest)
+			}
+		})
+	}
+}
+
+func TestSegments(t *testing.T) {
+	tr := NewWithCapacity[float64](4, 8)
+	for now := 0.0; now <= 6; now++ {
+		tr.Add(now, now)
+	}
+	pairs, ages := segments(tr, 6)
+
+	// Do mais recente ao mais antigo, só os trechos com idade até Length
+	want := [][2]float64{{6, 5}, {5, 4}, {4, 3}, {3, 2}}
+	if len(pairs) != len(want) {
+		t.Fatalf("trechos %v, esperados %v", pairs, want)
+	}
+	for i := range want {
+		if pairs[i] != want[i] {
+			t.Errorf("trecho %d = %v, esperado %v", i, pairs[i], want[i])
+		}
+		if wantAge := float64(i+1) / 4; math.Abs(ages[i]-wantAge) > 1e-12 {
+			t.Errorf("trecho %d: idade %v, esperada %v", i, ages[i], wantAge)
+		}
+	}
+}
+
+func TestCoversLength(t *testing.T) {
+	// Com passos muito menores que Length/capacidade, o buffer ainda cobre
+	// a duração inteira
+	const length, step = 10.0, 0.001
+	tr := NewWithCapacity[float64](length, 16)
+	now := 0.0
+	for ; now < 3*length; now += step {
+		tr.Add(now, now)
+	}
+	oldest := now
+	tr.Segments(now, func(_, b float64, _ float64) { oldest = b })
+	if span := now - oldest; span < length*0.85 {
+		t.Errorf("o rastro cobre %.2f, esperado perto de %v", span, length)
+	}
+}
+
+func TestReset(t *testing.T) {
+	tr := New[float64](1)
+	tr.Add(0, 0)
+	tr.Add(1, 0.5)
+	tr.Reset()
+	if tr.Len() != 0 {
+		t.Errorf("Len depois de Reset = %d", tr.Len())
+	}
+	if pairs, _ := segments(tr, 1); len(pairs) != 0 {
+		t.Errorf("trechos depois de Reset: %v", pairs)
+	}
+}